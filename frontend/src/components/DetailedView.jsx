@@ -28,12 +28,66 @@ ChartJS.register(
 
 const MAX_HISTORY = 60; // 60 * 2s = 120s history
 const MAX_LOG_LINES = 200; // Max lines to show in logs
+const ANNOTATION_WINDOW = '5m'; // Covers the charted history with room to spare
+const ANNOTATION_REFRESH_MS = 15000;
+
+const ANNOTATION_COLORS = {
+    config_change: 'rgb(234, 179, 8)',
+    restart: 'rgb(59, 130, 246)',
+    stop: 'rgb(156, 163, 175)',
+    crash_loop: 'rgb(239, 68, 68)',
+    anomaly: 'rgb(249, 115, 22)',
+};
+
+// Maps a time onto the category axis by interpolating between the two samples around it
+const annotationIndex = (timestamps, time) => {
+    for (let i = 0; i < timestamps.length; i++) {
+        if (timestamps[i] === null || timestamps[i] < time) continue;
+        if (i === 0 || timestamps[i - 1] === null) return timestamps[i] === time ? i : null;
+        const previous = timestamps[i - 1];
+        return i - 1 + (time - previous) / (timestamps[i] - previous);
+    }
+    return null; // After the latest sample
+};
+
+// Draws each annotation in options.items as a dashed vertical line at its timestamp.
+// options.timestamps holds the time of every charted point, in milliseconds.
+const annotationPlugin = {
+    id: 'annotations',
+    afterDatasetsDraw(chart, args, options) {
+        const { items = [], timestamps = [] } = options;
+        const { ctx, chartArea, scales } = chart;
+
+        items.forEach((annotation) => {
+            const index = annotationIndex(timestamps, Date.parse(annotation.timestamp));
+            if (index === null) return;
+            const x = scales.x.getPixelForValue(index);
+            if (x < chartArea.left || x > chartArea.right) return;
+
+            ctx.save();
+            ctx.strokeStyle = ANNOTATION_COLORS[annotation.type] || '#9ca3af';
+            ctx.fillStyle = ctx.strokeStyle;
+            ctx.lineWidth = 1.5;
+            ctx.setLineDash([4, 4]);
+            ctx.beginPath();
+            ctx.moveTo(x, chartArea.top);
+            ctx.lineTo(x, chartArea.bottom);
+            ctx.stroke();
+            ctx.beginPath();
+            ctx.arc(x, chartArea.top, 3, 0, 2 * Math.PI);
+            ctx.fill();
+            ctx.restore();
+        });
+    },
+};
 
 function DetailedView({ container, onClose, history }) {
     const [activeTab, setActiveTab] = useState('stats');
     const [logs, setLogs] = useState([]); // Change to array for easier line management
     const [processes, setProcesses] = useState({ Titles: [], Processes: [] });
     const [labels, setLabels] = useState(Array(history?.length || 0).fill(''));
+    const [timestamps, setTimestamps] = useState(Array(history?.length || 0).fill(null));
+    const [annotations, setAnnotations] = useState([]);
     const [cpuHistory, setCpuHistory] = useState([]);
     const [memHistory, setMemHistory] = useState(history || []);
     const [netInHistory, setNetInHistory] = useState([]);
@@ -49,13 +103,20 @@ function DetailedView({ container, onClose, history }) {
     }, [logs, activeTab, followLogs]);
 
     useEffect(() => {
-        const now = new Date().toLocaleTimeString();
+        const sampledAt = Date.now();
+        const now = new Date(sampledAt).toLocaleTimeString();
         setLabels((prev) => {
             const newLabels = [...prev, now];
             if (newLabels.length > MAX_HISTORY) newLabels.shift();
             return newLabels;
         });
 
+        setTimestamps((prev) => {
+            const newTimestamps = [...prev, sampledAt];
+            if (newTimestamps.length > MAX_HISTORY) newTimestamps.shift();
+            return newTimestamps;
+        });
+
         setCpuHistory((prev) => {
             const newHistory = [...prev, container.cpu_percent];
             if (newHistory.length > MAX_HISTORY) newHistory.shift();
@@ -81,6 +142,25 @@ function DetailedView({ container, onClose, history }) {
         });
     }, [container]);
 
+    // Restarts, stops, config changes and anomalies to overlay on the charts
+    useEffect(() => {
+        if (activeTab !== 'stats') return;
+
+        const fetchAnnotations = async () => {
+            try {
+                const response = await fetch(`/api/annotations/${container.id}?since=${ANNOTATION_WINDOW}`);
+                if (!response.ok) return; // History store disabled
+                setAnnotations(await response.json());
+            } catch (error) {
+                console.error('Error fetching annotations:', error);
+            }
+        };
+
+        fetchAnnotations();
+        const interval = setInterval(fetchAnnotations, ANNOTATION_REFRESH_MS);
+        return () => clearInterval(interval);
+    }, [activeTab, container.id]);
+
     useEffect(() => {
         if (activeTab === 'logs') {
             setLogs([]); // Clear logs when switching to logs tab or container changes
@@ -155,12 +235,15 @@ function DetailedView({ container, onClose, history }) {
         ],
     });
 
-    const chartOptions = (yAxisLabel) => ({
+    // Series that started after the initial history are drawn from the first label,
+    // so they are paired with the times of their own points
+    const chartOptions = (yAxisLabel, points) => ({
         responsive: true,
         maintainAspectRatio: false,
         plugins: {
             legend: { display: true, position: 'top', labels: { color: '#9ca3af' } },
             tooltip: { enabled: true },
+            annotations: { items: annotations, timestamps: points > 0 ? timestamps.slice(-points) : [] },
             zoom: {
                 pan: {
                     enabled: true,
@@ -301,7 +384,8 @@ function DetailedView({ container, onClose, history }) {
                                 <div className="chart-wrapper">
                                     <Line
                                         data={createChartData(cpuHistory, 'CPU %', 'rgb(59, 130, 246)')}
-                                        options={chartOptions('CPU %')}
+                                        options={chartOptions('CPU %', cpuHistory.length)}
+                                        plugins={[annotationPlugin]}
                                     />
                                 </div>
                             </div>
@@ -311,7 +395,8 @@ function DetailedView({ container, onClose, history }) {
                                 <div className="chart-wrapper">
                                     <Line
                                         data={createChartData(memHistory, 'Memory (MB)', 'rgb(168, 85, 247)')}
-                                        options={chartOptions('Memory (MB)')}
+                                        options={chartOptions('Memory (MB)', memHistory.length)}
+                                        plugins={[annotationPlugin]}
                                     />
                                 </div>
                             </div>
@@ -345,12 +430,33 @@ function DetailedView({ container, onClose, history }) {
                                                 },
                                             ],
                                         }}
-                                        options={chartOptions('KB')}
+                                        options={chartOptions('KB', netInHistory.length)}
+                                        plugins={[annotationPlugin]}
                                     />
                                 </div>
                             </div>
                         </div>
 
+                        {annotations.length > 0 && (
+                            <div className="annotations-section">
+                                <h3 className="info-title">Recent Events</h3>
+                                <ul className="annotations-list">
+                                    {annotations.map((annotation, index) => (
+                                        <li key={index} className="annotation-item">
+                                            <span
+                                                className="annotation-marker"
+                                                style={{ backgroundColor: ANNOTATION_COLORS[annotation.type] || '#9ca3af' }}
+                                            />
+                                            <span className="annotation-time">
+                                                {new Date(annotation.timestamp).toLocaleTimeString()}
+                                            </span>
+                                            <span className="annotation-label">{annotation.label}</span>
+                                        </li>
+                                    ))}
+                                </ul>
+                            </div>
+                        )}
+
                         {/* Container Info */}
                         <div className="info-section">
                             <h3 className="info-title">Container Information</h3>
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds runtime settings read from the environment
+type Config struct {
+	// SecretPatterns are matched case-insensitively against environment variable
+	// names; matching values are redacted in API responses
+	SecretPatterns []string
+
+	// AdminToken grants the elevated role to callers presenting it as a bearer token.
+	// An empty token disables the elevated role entirely.
+	AdminToken string
+
+	// SecretScanPatterns are extra "name=regex" patterns for the secret scanner
+	SecretScanPatterns []string
+
+	// LogRedaction controls how secrets in served logs are masked: "off", "mask" or "partial"
+	LogRedaction string
+
+	// SampleInterval is how often the background sampler runs crash-loop and anomaly detection
+	SampleInterval time.Duration
+
+	// RollupInterval is how often the sampler stores a downsampled metric snapshot of
+	// every running container, kept for a month; 0 disables rollups
+	RollupInterval time.Duration
+
+	// UpdateCheckInterval is how often image tags are compared with the registry; 0 disables checks
+	UpdateCheckInterval time.Duration
+
+	// UpdateRegistry overrides the registry derived from each image reference,
+	// e.g. "http://localhost:5000" for a mirror or local registry:2
+	UpdateRegistry string
+
+	// UpdateSemver also reports newer version tags, e.g. 1.25.4 when running 1.25.3
+	UpdateSemver bool
+
+	// DiskUsageInterval is how often Docker disk usage is recorded; 0 disables tracking
+	DiskUsageInterval time.Duration
+
+	// ProtectedLabel marks containers, images, volumes and networks that prunes must keep
+	// when set to a true value, e.g. gocontainerops.protected=true
+	ProtectedLabel string
+
+	// HostProcPath and HostSysPath locate the host's /proc and /sys, which differ
+	// when running in a container with them mounted (e.g. /host/proc)
+	HostProcPath string
+	HostSysPath  string
+
+	// HostDiskPath is the filesystem whose usage is reported, usually Docker's data root
+	HostDiskPath string
+
+	// HostInterval is how often host metrics are sampled; 0 disables the host collector
+	HostInterval time.Duration
+
+	// StreamInterval is how often the shared collector behind /api/stream/stats samples
+	StreamInterval time.Duration
+
+	// SLOLabel is the container label holding an availability objective, e.g. "99.9"
+	SLOLabel string
+
+	// ForecastInterval is how often memory and disk exhaustion are forecast; 0 disables forecasts
+	ForecastInterval time.Duration
+
+	// ForecastHorizon is how close a predicted exhaustion must be to raise an alert
+	ForecastHorizon time.Duration
+
+	// RecommendationLookback is the default history used for right-sizing recommendations
+	RecommendationLookback time.Duration
+
+	// ContentionInterval is how often host pressure and container usage are checked for
+	// contention; 0 disables detection, which also needs the host collector
+	ContentionInterval time.Duration
+
+	// Cost rates used to split host cost. Per-vCPU and per-GB rates take precedence;
+	// otherwise the hourly host cost is split evenly between CPU and memory.
+	CostHostHourly     float64
+	CostCPUHourly      float64
+	CostMemoryGBHourly float64
+	CostCurrency       string
+}
+
+// Load reads the configuration from environment variables, falling back to defaults
+func Load() Config {
+	return Config{
+		SecretPatterns: getList("GOCONTAINEROPS_SECRET_PATTERNS", []string{"PASSWORD", "TOKEN", "KEY", "SECRET"}),
+		AdminToken:     os.Getenv("GOCONTAINEROPS_ADMIN_TOKEN"),
+		// Regular expressions often contain commas, so custom patterns are separated by semicolons
+		SecretScanPatterns:     getListSep("GOCONTAINEROPS_SECRET_SCAN_PATTERNS", ";", nil),
+		LogRedaction:           getString("GOCONTAINEROPS_LOG_REDACTION", "mask"),
+		SampleInterval:         getDuration("GOCONTAINEROPS_SAMPLE_INTERVAL", 10*time.Second),
+		RollupInterval:         getDuration("GOCONTAINEROPS_ROLLUP_INTERVAL", 5*time.Minute),
+		UpdateCheckInterval:    getDuration("GOCONTAINEROPS_UPDATE_INTERVAL", 6*time.Hour),
+		UpdateRegistry:         os.Getenv("GOCONTAINEROPS_UPDATE_REGISTRY"),
+		UpdateSemver:           getBool("GOCONTAINEROPS_UPDATE_SEMVER", false),
+		DiskUsageInterval:      getDuration("GOCONTAINEROPS_DISK_USAGE_INTERVAL", 15*time.Minute),
+		ProtectedLabel:         getString("GOCONTAINEROPS_PROTECTED_LABEL", "gocontainerops.protected"),
+		HostProcPath:           getString("GOCONTAINEROPS_HOST_PROC", "/proc"),
+		HostSysPath:            getString("GOCONTAINEROPS_HOST_SYS", "/sys"),
+		HostDiskPath:           getString("GOCONTAINEROPS_HOST_DISK_PATH", "/"),
+		HostInterval:           getDuration("GOCONTAINEROPS_HOST_INTERVAL", 10*time.Second),
+		StreamInterval:         getDuration("GOCONTAINEROPS_STREAM_INTERVAL", 2*time.Second),
+		SLOLabel:               getString("GOCONTAINEROPS_SLO_LABEL", "gocontainerops.slo"),
+		ForecastInterval:       getDuration("GOCONTAINEROPS_FORECAST_INTERVAL", 5*time.Minute),
+		ForecastHorizon:        getDuration("GOCONTAINEROPS_FORECAST_HORIZON", 24*time.Hour),
+		RecommendationLookback: getDuration("GOCONTAINEROPS_RECOMMENDATION_LOOKBACK", 24*time.Hour),
+		ContentionInterval:     getDuration("GOCONTAINEROPS_CONTENTION_INTERVAL", 10*time.Second),
+		CostHostHourly:         getFloat("GOCONTAINEROPS_COST_HOST_HOURLY", 0),
+		CostCPUHourly:          getFloat("GOCONTAINEROPS_COST_VCPU_HOURLY", 0),
+		CostMemoryGBHourly:     getFloat("GOCONTAINEROPS_COST_GB_HOURLY", 0),
+		CostCurrency:           getString("GOCONTAINEROPS_COST_CURRENCY", "USD"),
+	}
+}
+
+// getString reads a string, falling back to a default when unset or empty
+func getString(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDuration reads a Go duration such as "30m", falling back to a default when unset or invalid
+func getDuration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s %q: %v", name, value, err)
+		return fallback
+	}
+	return d
+}
+
+// getBool reads a boolean such as "true" or "1", falling back to a default when unset or invalid
+func getBool(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s %q: %v", name, value, err)
+		return fallback
+	}
+	return b
+}
+
+// getFloat reads a decimal number such as "0.042", falling back to a default when unset or invalid
+func getFloat(name string, fallback float64) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		log.Printf("Ignoring invalid %s %q", name, value)
+		return fallback
+	}
+	return f
+}
+
+// getList reads a comma-separated list, ignoring empty entries
+func getList(name string, fallback []string) []string {
+	return getListSep(name, ",", fallback)
+}
+
+// getListSep reads a list using the given separator, ignoring empty entries
+func getListSep(name, sep string, fallback []string) []string {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -1,9 +1,15 @@
 package container
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
+// NoGroup is the group of containers that have no value for the grouping key
+const NoGroup = "(none)"
+
 // CalculateAggregateMetrics computes system-wide aggregate statistics
 func CalculateAggregateMetrics(containers []ContainerData) AggregateMetrics {
 	metrics := AggregateMetrics{
@@ -14,7 +20,7 @@ func CalculateAggregateMetrics(containers []ContainerData) AggregateMetrics {
 		return metrics
 	}
 
-	var runningCount int
+	var runningCount, unhealthyCount int
 	var totalCPU, totalMem, totalMemLimit float64
 	var totalNetIn, totalNetOut, totalBlkIn, totalBlkOut float64
 	var maxRestarts int
@@ -28,6 +34,11 @@ func CalculateAggregateMetrics(containers []ContainerData) AggregateMetrics {
 			runningCount++
 		}
 
+		// Count containers failing their health check
+		if c.Health != nil && c.Health.Status == "unhealthy" {
+			unhealthyCount++
+		}
+
 		// Aggregate metrics
 		totalCPU += c.CPUPercent
 		totalMem += c.MemUsage
@@ -46,6 +57,7 @@ func CalculateAggregateMetrics(containers []ContainerData) AggregateMetrics {
 
 	metrics.RunningContainers = runningCount
 	metrics.StoppedContainers = metrics.TotalContainers - runningCount
+	metrics.UnhealthyContainers = unhealthyCount
 	metrics.TotalCPUPercent = totalCPU
 	metrics.TotalMemUsage = totalMem
 	metrics.TotalMemLimit = totalMemLimit
@@ -74,6 +86,103 @@ func CalculateAggregateMetrics(containers []ContainerData) AggregateMetrics {
 	return metrics
 }
 
+// CalculateHostCapacity relates container totals to the host's cores and RAM.
+// Docker reports the host's RAM as the limit of unlimited containers, so those are
+// counted separately instead of being summed into the committed limits.
+func CalculateHostCapacity(containers []ContainerData, cpuCores int, memTotal float64) *HostCapacity {
+	capacity := &HostCapacity{CPUCores: cpuCores, MemTotal: memTotal}
+
+	var totalCPU, totalMem float64
+	for _, c := range containers {
+		totalCPU += c.CPUPercent
+		totalMem += c.MemUsage
+
+		if c.State != "running" {
+			continue
+		}
+		// Allow for rounding between the cgroup limit and /proc/meminfo
+		if c.MemLimit <= 0 || c.MemLimit >= memTotal*0.99 {
+			capacity.UnlimitedContainers++
+			continue
+		}
+		capacity.CommittedMemLimit += c.MemLimit
+	}
+
+	// Container CPU percentages are relative to one core
+	if cpuCores > 0 {
+		capacity.CPUShare = totalCPU / float64(cpuCores)
+	}
+	if memTotal > 0 {
+		capacity.MemShare = totalMem / memTotal * 100.0
+		capacity.MemOvercommitRatio = capacity.CommittedMemLimit / memTotal
+		capacity.Overcommitted = capacity.MemOvercommitRatio > 1
+	}
+
+	return capacity
+}
+
+// CalculateGroupedAggregateMetrics computes aggregate statistics per group.
+// groupBy is one of "image", "host", "project" or "label:<key>".
+func CalculateGroupedAggregateMetrics(containers []ContainerData, groupBy string) ([]GroupedMetrics, error) {
+	groups, err := GroupContainers(containers, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]GroupedMetrics, 0, len(groups))
+	for key, members := range groups {
+		result = append(result, GroupedMetrics{
+			Group:   key,
+			Metrics: CalculateAggregateMetrics(members),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Group < result[j].Group
+	})
+
+	return result, nil
+}
+
+// GroupContainers splits containers by the key groupBy selects; containers without
+// a key are grouped under NoGroup
+func GroupContainers(containers []ContainerData, groupBy string) (map[string][]ContainerData, error) {
+	keyFunc, err := GroupKeyFunc(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]ContainerData)
+	for _, c := range containers {
+		key := keyFunc(c)
+		if key == "" {
+			key = NoGroup
+		}
+		groups[key] = append(groups[key], c)
+	}
+	return groups, nil
+}
+
+// GroupKeyFunc returns the function extracting the grouping key of a container
+func GroupKeyFunc(groupBy string) (func(ContainerData) string, error) {
+	if label, found := strings.CutPrefix(groupBy, "label:"); found {
+		if label == "" {
+			return nil, fmt.Errorf("group_by label key must not be empty")
+		}
+		return func(c ContainerData) string { return c.Labels[label] }, nil
+	}
+
+	switch groupBy {
+	case "image":
+		return func(c ContainerData) string { return c.Image }, nil
+	case "host":
+		return func(c ContainerData) string { return c.Host }, nil
+	case "project":
+		return func(c ContainerData) string { return c.Project }, nil
+	}
+
+	return nil, fmt.Errorf("unsupported group_by %q (use image, host, project or label:<key>)", groupBy)
+}
+
 // CalculateUptime calculates container uptime in seconds
 func CalculateUptime(created int64, state string) int64 {
 	if state != "running" {
@@ -0,0 +1,222 @@
+package container
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metric names checked by the anomaly detector
+const (
+	AnomalyMetricCPU       = "cpu_percent"
+	AnomalyMetricMemory    = "mem_usage"
+	AnomalyMetricNetInput  = "net_input_kbps"
+	AnomalyMetricNetOutput = "net_output_kbps"
+)
+
+// AnomalyConfig holds the parameters of the per-container baselines
+type AnomalyConfig struct {
+	Alpha         float64            // EWMA smoothing factor; smaller values give a longer memory
+	Threshold     float64            // z-score at which a sample is anomalous
+	WarmupSamples int                // samples observed before a baseline is trusted
+	MinStdDev     map[string]float64 // noise floor per metric, so idle services don't flag on tiny changes
+}
+
+// DefaultAnomalyConfig returns the default anomaly detection parameters
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Alpha:         0.05,
+		Threshold:     4,
+		WarmupSamples: 30,
+		MinStdDev: map[string]float64{
+			AnomalyMetricCPU:       2,  // percentage points
+			AnomalyMetricMemory:    8,  // MB
+			AnomalyMetricNetInput:  16, // KB/s
+			AnomalyMetricNetOutput: 16, // KB/s
+		},
+	}
+}
+
+// Anomaly describes a metric deviating from its container's baseline
+type Anomaly struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Metric   string    `json:"metric"`
+	Value    float64   `json:"value"`
+	Expected float64   `json:"expected"`
+	Lower    float64   `json:"lower"`
+	Upper    float64   `json:"upper"`
+	Score    float64   `json:"score"` // z-score; negative below the baseline
+	Since    time.Time `json:"since"`
+}
+
+// AnomalyDetector keeps an exponentially weighted mean and variance of every
+// container's CPU, memory and network rates and flags samples whose z-score
+// exceeds the threshold. Baselines keep adapting, so a lasting change in load
+// stops being anomalous once the baseline has caught up.
+type AnomalyDetector struct {
+	config     AnomalyConfig
+	mu         sync.Mutex
+	containers map[string]*anomalyState
+}
+
+type anomalyState struct {
+	name       string
+	lastSample time.Time
+	netInput   float64
+	netOutput  float64
+	baselines  map[string]*baseline
+	active     map[string]*Anomaly
+}
+
+type baseline struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+// NewAnomalyDetector creates a detector with the given parameters
+func NewAnomalyDetector(config AnomalyConfig) *AnomalyDetector {
+	return &AnomalyDetector{
+		config:     config,
+		containers: make(map[string]*anomalyState),
+	}
+}
+
+// Observe feeds a fresh sample of containers into their baselines and returns the
+// anomalies that started and the ones that resolved with this sample
+func (d *AnomalyDetector) Observe(containers []ContainerData, now time.Time) (started, resolved []Anomaly) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, c := range containers {
+		if c.State != "running" {
+			continue
+		}
+
+		state, exists := d.containers[c.ID]
+		if !exists {
+			state = &anomalyState{
+				baselines: make(map[string]*baseline),
+				active:    make(map[string]*Anomaly),
+			}
+			d.containers[c.ID] = state
+		}
+		state.name = c.Name
+
+		values := map[string]float64{
+			AnomalyMetricCPU:    c.CPUPercent,
+			AnomalyMetricMemory: c.MemUsage,
+		}
+
+		// Network counters are cumulative; compare rates. A counter going
+		// backwards means the container restarted, so skip that interval.
+		if !state.lastSample.IsZero() {
+			elapsed := now.Sub(state.lastSample).Seconds()
+			if elapsed > 0 && c.NetInput >= state.netInput && c.NetOutput >= state.netOutput {
+				values[AnomalyMetricNetInput] = (c.NetInput - state.netInput) / elapsed
+				values[AnomalyMetricNetOutput] = (c.NetOutput - state.netOutput) / elapsed
+			}
+		}
+		state.lastSample = now
+		state.netInput = c.NetInput
+		state.netOutput = c.NetOutput
+
+		for metric, value := range values {
+			b, exists := state.baselines[metric]
+			if !exists {
+				b = &baseline{mean: value}
+				state.baselines[metric] = b
+			}
+
+			anomaly, flagged := d.check(b, metric, value)
+			if flagged {
+				anomaly.ID = c.ID
+				anomaly.Name = c.Name
+				if current, active := state.active[metric]; active {
+					anomaly.Since = current.Since
+				} else {
+					anomaly.Since = now
+					started = append(started, anomaly)
+				}
+				state.active[metric] = &anomaly
+			} else if current, active := state.active[metric]; active {
+				delete(state.active, metric)
+				resolved = append(resolved, *current)
+			}
+
+			b.update(value, d.config.Alpha)
+		}
+	}
+
+	return started, resolved
+}
+
+// check compares a value against the baseline before the value is folded into it
+func (d *AnomalyDetector) check(b *baseline, metric string, value float64) (Anomaly, bool) {
+	if b.samples < d.config.WarmupSamples {
+		return Anomaly{}, false
+	}
+
+	stddev := math.Max(math.Sqrt(b.variance), d.config.MinStdDev[metric])
+	if stddev == 0 {
+		return Anomaly{}, false
+	}
+
+	score := (value - b.mean) / stddev
+	if math.Abs(score) < d.config.Threshold {
+		return Anomaly{}, false
+	}
+
+	return Anomaly{
+		Metric:   metric,
+		Value:    value,
+		Expected: b.mean,
+		Lower:    math.Max(b.mean-d.config.Threshold*stddev, 0),
+		Upper:    b.mean + d.config.Threshold*stddev,
+		Score:    score,
+	}, true
+}
+
+// update folds a value into the exponentially weighted mean and variance
+func (b *baseline) update(value, alpha float64) {
+	b.samples++
+	if b.samples == 1 {
+		b.mean = value
+		return
+	}
+	diff := value - b.mean
+	increment := alpha * diff
+	b.mean += increment
+	b.variance = (1 - alpha) * (b.variance + diff*increment)
+}
+
+// Active returns the anomalies currently in progress, highest score first
+func (d *AnomalyDetector) Active() []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []Anomaly
+	for _, state := range d.containers {
+		for _, anomaly := range state.active {
+			result = append(result, *anomaly)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if math.Abs(result[i].Score) != math.Abs(result[j].Score) {
+			return math.Abs(result[i].Score) > math.Abs(result[j].Score)
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// Forget drops the baselines of a removed container
+func (d *AnomalyDetector) Forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.containers, id)
+}
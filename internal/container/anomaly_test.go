@@ -0,0 +1,128 @@
+package container
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBaseline_Update(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       []float64
+		wantMean     float64
+		wantVariance float64
+	}{
+		{"single sample", []float64{42}, 42, 0},
+		{"constant", []float64{5, 5, 5, 5}, 5, 0},
+		// diff 10, increment 5: mean 15, variance 0.5 * (0 + 10*5)
+		{"two samples", []float64{10, 20}, 15, 25},
+	}
+
+	for _, tt := range tests {
+		b := &baseline{}
+		for _, v := range tt.values {
+			b.update(v, 0.5)
+		}
+		if b.samples != len(tt.values) || math.Abs(b.mean-tt.wantMean) > 1e-9 || math.Abs(b.variance-tt.wantVariance) > 1e-9 {
+			t.Errorf("%s: got mean %v variance %v after %d samples, want %v and %v", tt.name, b.mean, b.variance, b.samples, tt.wantMean, tt.wantVariance)
+		}
+	}
+}
+
+func TestAnomalyDetector_Observe(t *testing.T) {
+	config := DefaultAnomalyConfig()
+	config.WarmupSamples = 5
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		cpu       []float64 // one sample every 10 seconds, the last one is checked
+		state     string
+		wantScore float64 // sign of the expected z-score, 0 for no anomaly
+	}{
+		{name: "empty history", cpu: []float64{90}},
+		{name: "spike during warmup", cpu: []float64{10, 10, 10, 90}},
+		{name: "spike after warmup", cpu: []float64{10, 10, 10, 10, 10, 10, 90}, wantScore: 1},
+		{name: "drop after warmup", cpu: []float64{60, 60, 60, 60, 60, 60, 5}, wantScore: -1},
+		// The noise floor of 2 points times the threshold of 4 allows a swing of 8
+		{name: "within the noise floor", cpu: []float64{10, 10, 10, 10, 10, 10, 17}},
+		{name: "stopped container", cpu: []float64{10, 10, 10, 10, 10, 10, 90}, state: "exited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewAnomalyDetector(config)
+			state := tt.state
+			if state == "" {
+				state = "running"
+			}
+
+			var started []Anomaly
+			for i, cpu := range tt.cpu {
+				data := ContainerData{ID: "web", Name: "web", State: state, CPUPercent: cpu, MemUsage: 100}
+				started, _ = d.Observe([]ContainerData{data}, start.Add(time.Duration(i)*10*time.Second))
+			}
+
+			if tt.wantScore == 0 {
+				if len(started) != 0 {
+					t.Fatalf("started %+v, want none", started)
+				}
+				return
+			}
+			if len(started) != 1 || started[0].Metric != AnomalyMetricCPU {
+				t.Fatalf("started %+v, want one cpu anomaly", started)
+			}
+			anomaly := started[0]
+			if anomaly.Score*tt.wantScore < config.Threshold {
+				t.Errorf("score %v, want beyond %v in the direction of %v", anomaly.Score, config.Threshold, tt.wantScore)
+			}
+			if anomaly.Value >= anomaly.Lower && anomaly.Value <= anomaly.Upper {
+				t.Errorf("value %v inside the expected range [%v, %v]", anomaly.Value, anomaly.Lower, anomaly.Upper)
+			}
+		})
+	}
+}
+
+func TestAnomalyDetector_ResolveAndNetworkReset(t *testing.T) {
+	config := DefaultAnomalyConfig()
+	config.WarmupSamples = 3
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewAnomalyDetector(config)
+
+	observe := func(i int, cpu, netInput float64) ([]Anomaly, []Anomaly) {
+		data := ContainerData{ID: "web", Name: "web", State: "running", CPUPercent: cpu, NetInput: netInput}
+		return d.Observe([]ContainerData{data}, start.Add(time.Duration(i)*10*time.Second))
+	}
+
+	// Steady 10 KB/s of input
+	for i := 0; i < 5; i++ {
+		observe(i, 10, float64(i)*100)
+	}
+
+	// The counter going backwards after a restart is not a rate drop
+	if started, _ := observe(5, 10, 0); len(started) != 0 {
+		t.Errorf("counter reset flagged: %+v", started)
+	}
+
+	started, _ := observe(6, 95, 100)
+	if len(started) != 1 || len(d.Active()) != 1 {
+		t.Fatalf("spike not flagged: started %+v", started)
+	}
+
+	// Still anomalous: not reported as started again, and keeps its start time
+	started, _ = observe(7, 95, 200)
+	if len(started) != 0 || !d.Active()[0].Since.Equal(start.Add(60*time.Second)) {
+		t.Errorf("ongoing anomaly restarted: %+v, active %+v", started, d.Active())
+	}
+
+	_, resolved := observe(8, 10, 300)
+	if len(resolved) != 1 || len(d.Active()) != 0 {
+		t.Errorf("return to baseline not resolved: resolved %+v, active %+v", resolved, d.Active())
+	}
+
+	d.Forget("web")
+	if started, _ := observe(9, 95, 400); len(started) != 0 {
+		t.Errorf("forgotten container kept its baseline: %+v", started)
+	}
+}
@@ -0,0 +1,96 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Labels set by Docker Compose on every container it creates
+const (
+	ComposeProjectLabel   = "com.docker.compose.project"
+	ComposeServiceLabel   = "com.docker.compose.service"
+	ComposeNumberLabel    = "com.docker.compose.container-number"
+	ComposeDependsOnLabel = "com.docker.compose.depends_on"
+)
+
+// ComposeInfo holds the compose metadata parsed from container labels
+type ComposeInfo struct {
+	Project         string
+	Service         string
+	ContainerNumber int
+	DependsOn       []string
+}
+
+// ParseCompose extracts compose metadata from container labels.
+// The depends_on label has the form "db:service_healthy:false,cache:service_started:true".
+func ParseCompose(labels map[string]string) ComposeInfo {
+	info := ComposeInfo{
+		Project: labels[ComposeProjectLabel],
+		Service: labels[ComposeServiceLabel],
+	}
+
+	if number, err := strconv.Atoi(labels[ComposeNumberLabel]); err == nil {
+		info.ContainerNumber = number
+	}
+
+	for _, dependency := range strings.Split(labels[ComposeDependsOnLabel], ",") {
+		service, _, _ := strings.Cut(strings.TrimSpace(dependency), ":")
+		if service != "" {
+			info.DependsOn = append(info.DependsOn, service)
+		}
+	}
+
+	return info
+}
+
+// ServiceStartOrder sorts services so that every service comes after the ones it
+// depends on. Dependencies outside the given set are ignored; cycles are an error.
+func ServiceStartOrder(dependencies map[string][]string) ([]string, error) {
+	inDegree := make(map[string]int, len(dependencies))
+	dependents := make(map[string][]string)
+
+	for service := range dependencies {
+		inDegree[service] += 0
+	}
+	for service, deps := range dependencies {
+		for _, dep := range deps {
+			if _, known := dependencies[dep]; !known || dep == service {
+				continue
+			}
+			inDegree[service]++
+			dependents[dep] = append(dependents[dep], service)
+		}
+	}
+
+	var ready []string
+	for service, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, service)
+		}
+	}
+	sort.Strings(ready)
+
+	order := make([]string, 0, len(dependencies))
+	for len(ready) > 0 {
+		service := ready[0]
+		ready = ready[1:]
+		order = append(order, service)
+
+		next := dependents[service]
+		sort.Strings(next)
+		for _, dependent := range next {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(dependencies) {
+		return nil, fmt.Errorf("dependency cycle between compose services")
+	}
+
+	return order, nil
+}
@@ -0,0 +1,11 @@
+package container
+
+// ConfigKey identifies a container across redeployments: the compose
+// "project/service" when available, the container name otherwise
+func ConfigKey(labels map[string]string, name string) string {
+	compose := ParseCompose(labels)
+	if compose.Project != "" && compose.Service != "" {
+		return compose.Project + "/" + compose.Service
+	}
+	return name
+}
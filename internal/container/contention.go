@@ -0,0 +1,306 @@
+package container
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Contended resources
+const (
+	ContentionCPU = "cpu"
+	ContentionIO  = "io"
+)
+
+// ContentionConfig holds the thresholds used to detect contention episodes
+type ContentionConfig struct {
+	CPUPressure        float64 // host PSI cpu "some" avg10 (%) that starts an episode
+	IOPressure         float64 // host PSI io "some" avg10 (%) that starts an episode
+	ClearRatio         float64 // an episode ends once pressure falls below threshold * ClearRatio
+	MinDuration        time.Duration
+	ThrottledThreshold float64 // share of CPU periods throttled that marks a victim
+	DropThreshold      float64 // relative drop from baseline usage that marks a victim
+	MinBaselineCPU     float64 // CPU percent below which usage drops are ignored
+	MinBaselineIO      float64 // KB/s below which I/O drops are ignored
+	BaselineAlpha      float64 // EWMA smoothing of the pre-episode baselines
+	TopContributors    int
+	RecentEpisodes     int // ended episodes kept for reporting
+}
+
+// DefaultContentionConfig returns the default contention thresholds
+func DefaultContentionConfig() ContentionConfig {
+	return ContentionConfig{
+		CPUPressure:        20,
+		IOPressure:         20,
+		ClearRatio:         0.5,
+		MinDuration:        30 * time.Second,
+		ThrottledThreshold: 0.1,
+		DropThreshold:      0.3,
+		MinBaselineCPU:     5,
+		MinBaselineIO:      64,
+		BaselineAlpha:      0.1,
+		TopContributors:    5,
+		RecentEpisodes:     100,
+	}
+}
+
+// ContentionSample holds one container's usage over the last sampling interval
+type ContentionSample struct {
+	ID             string
+	Name           string
+	CPUPercent     float64
+	IOKBps         float64 // block reads plus writes
+	ThrottledRatio float64 // share of CPU periods throttled during the interval
+}
+
+// ContentionContributor is a container consuming the contended resource
+type ContentionContributor struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Share float64 `json:"share"` // of all container usage of the resource during the episode
+	Usage float64 `json:"usage"` // average CPU percent or I/O KB/s during the episode
+}
+
+// ContentionVictim is a container whose performance dropped during an episode
+type ContentionVictim struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Reason         string  `json:"reason"` // "throttled", "usage_drop"
+	Baseline       float64 `json:"baseline"`
+	During         float64 `json:"during"`
+	ThrottledRatio float64 `json:"throttled_ratio"`
+}
+
+// ContentionEpisode is a period of host pressure on one resource
+type ContentionEpisode struct {
+	Resource        string                  `json:"resource"` // "cpu", "io"
+	Start           time.Time               `json:"start"`
+	End             *time.Time              `json:"end,omitempty"`
+	Active          bool                    `json:"active"`
+	DurationSeconds float64                 `json:"duration_seconds"`
+	PeakPressure    float64                 `json:"peak_pressure"`
+	Contributors    []ContentionContributor `json:"contributors"`
+	Victims         []ContentionVictim      `json:"victims"`
+}
+
+// ContentionDetector turns host pressure and per-container usage into contention
+// episodes. Outside episodes it keeps a baseline of every container's usage, so the
+// containers whose usage drops or which get throttled during an episode can be named.
+type ContentionDetector struct {
+	config    ContentionConfig
+	mu        sync.Mutex
+	baselines map[string]*contentionBaseline
+	active    map[string]*episodeState
+	recent    []ContentionEpisode
+}
+
+type contentionBaseline struct {
+	cpu     float64
+	io      float64
+	samples int
+}
+
+type episodeState struct {
+	episode ContentionEpisode
+	usage   map[string]*episodeUsage
+}
+
+type episodeUsage struct {
+	name      string
+	cpu       float64
+	io        float64
+	throttled float64
+	samples   int
+}
+
+// NewContentionDetector creates a detector with the given thresholds
+func NewContentionDetector(config ContentionConfig) *ContentionDetector {
+	return &ContentionDetector{
+		config:    config,
+		baselines: make(map[string]*contentionBaseline),
+		active:    make(map[string]*episodeState),
+	}
+}
+
+// Observe feeds the current host pressure (PSI "some" avg10 per resource) and the
+// containers' usage since the previous call. It returns the episodes that ended;
+// episodes shorter than the minimum duration are dropped as noise.
+func (d *ContentionDetector) Observe(pressure map[string]float64, samples []ContentionSample, now time.Time) []ContentionEpisode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	thresholds := map[string]float64{
+		ContentionCPU: d.config.CPUPressure,
+		ContentionIO:  d.config.IOPressure,
+	}
+
+	var ended []ContentionEpisode
+	for _, resource := range []string{ContentionCPU, ContentionIO} {
+		value := pressure[resource]
+		state, active := d.active[resource]
+
+		switch {
+		case !active && value >= thresholds[resource]:
+			state = &episodeState{
+				episode: ContentionEpisode{Resource: resource, Start: now, Active: true},
+				usage:   make(map[string]*episodeUsage),
+			}
+			d.active[resource] = state
+		case active && value < thresholds[resource]*d.config.ClearRatio:
+			delete(d.active, resource)
+			episode := d.summarize(state, now)
+			episode.Active = false
+			episode.End = &now
+			if episode.DurationSeconds >= d.config.MinDuration.Seconds() {
+				ended = append(ended, episode)
+				d.recent = append(d.recent, episode)
+			}
+			continue
+		case !active:
+			continue
+		}
+
+		if value > state.episode.PeakPressure {
+			state.episode.PeakPressure = value
+		}
+		for _, sample := range samples {
+			usage, exists := state.usage[sample.ID]
+			if !exists {
+				usage = &episodeUsage{}
+				state.usage[sample.ID] = usage
+			}
+			usage.name = sample.Name
+			usage.cpu += sample.CPUPercent
+			usage.io += sample.IOKBps
+			usage.throttled += sample.ThrottledRatio
+			usage.samples++
+		}
+	}
+
+	// Baselines only learn from uncontended periods
+	if len(d.active) == 0 && len(samples) > 0 {
+		seen := make(map[string]bool)
+		for _, sample := range samples {
+			seen[sample.ID] = true
+			b, exists := d.baselines[sample.ID]
+			if !exists {
+				d.baselines[sample.ID] = &contentionBaseline{cpu: sample.CPUPercent, io: sample.IOKBps, samples: 1}
+				continue
+			}
+			b.cpu += d.config.BaselineAlpha * (sample.CPUPercent - b.cpu)
+			b.io += d.config.BaselineAlpha * (sample.IOKBps - b.io)
+			b.samples++
+		}
+		for id := range d.baselines {
+			if !seen[id] {
+				delete(d.baselines, id)
+			}
+		}
+	}
+
+	if len(d.recent) > d.config.RecentEpisodes {
+		d.recent = d.recent[len(d.recent)-d.config.RecentEpisodes:]
+	}
+
+	return ended
+}
+
+// summarize ranks the contributors and victims of an episode so far
+func (d *ContentionDetector) summarize(state *episodeState, now time.Time) ContentionEpisode {
+	episode := state.episode
+	episode.DurationSeconds = now.Sub(episode.Start).Seconds()
+	episode.Contributors = []ContentionContributor{}
+	episode.Victims = []ContentionVictim{}
+
+	var total float64
+	for _, usage := range state.usage {
+		total += d.resourceUsage(episode.Resource, usage)
+	}
+
+	for id, usage := range state.usage {
+		average := d.resourceUsage(episode.Resource, usage) / float64(usage.samples)
+		if total > 0 && average > 0 {
+			episode.Contributors = append(episode.Contributors, ContentionContributor{
+				ID:    id,
+				Name:  usage.name,
+				Share: d.resourceUsage(episode.Resource, usage) / total,
+				Usage: average,
+			})
+		}
+
+		throttled := usage.throttled / float64(usage.samples)
+		victim := ContentionVictim{ID: id, Name: usage.name, During: average, ThrottledRatio: throttled}
+
+		baseline, known := d.baselines[id]
+		if known {
+			victim.Baseline = baseline.cpu
+			floor := d.config.MinBaselineCPU
+			if episode.Resource == ContentionIO {
+				victim.Baseline = baseline.io
+				floor = d.config.MinBaselineIO
+			}
+			if victim.Baseline >= floor && average < victim.Baseline*(1-d.config.DropThreshold) {
+				victim.Reason = "usage_drop"
+			}
+		}
+		if episode.Resource == ContentionCPU && throttled >= d.config.ThrottledThreshold {
+			victim.Reason = "throttled"
+		}
+		if victim.Reason != "" {
+			episode.Victims = append(episode.Victims, victim)
+		}
+	}
+
+	sort.Slice(episode.Contributors, func(i, j int) bool {
+		return episode.Contributors[i].Share > episode.Contributors[j].Share
+	})
+	if len(episode.Contributors) > d.config.TopContributors {
+		episode.Contributors = episode.Contributors[:d.config.TopContributors]
+	}
+	sort.Slice(episode.Victims, func(i, j int) bool {
+		return episode.Victims[i].ID < episode.Victims[j].ID
+	})
+
+	return episode
+}
+
+// resourceUsage returns the summed usage of the contended resource
+func (d *ContentionDetector) resourceUsage(resource string, usage *episodeUsage) float64 {
+	if resource == ContentionIO {
+		return usage.io
+	}
+	return usage.cpu
+}
+
+// Active returns the episodes in progress
+func (d *ContentionDetector) Active(now time.Time) []ContentionEpisode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []ContentionEpisode
+	for _, state := range d.active {
+		result = append(result, d.summarize(state, now))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return result
+}
+
+// Recent returns ended episodes that overlap a time range, newest first
+func (d *ContentionDetector) Recent(since, until time.Time) []ContentionEpisode {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []ContentionEpisode
+	for i := len(d.recent) - 1; i >= 0; i-- {
+		episode := d.recent[i]
+		if episode.End.Before(since) || episode.Start.After(until) {
+			continue
+		}
+		result = append(result, episode)
+	}
+
+	return result
+}
@@ -0,0 +1,102 @@
+package container
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestContentionDetector_Episodes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		pressure   []float64 // cpu pressure, one observation every 10 seconds
+		wantEnded  int
+		wantActive bool
+	}{
+		{name: "no observations"},
+		{name: "no pressure", pressure: []float64{0, 5, 19}},
+		{name: "single observation over the threshold", pressure: []float64{50}, wantActive: true},
+		{name: "shorter than the minimum duration", pressure: []float64{50, 50, 0}},
+		{name: "ends after the minimum duration", pressure: []float64{50, 50, 50, 50, 0}, wantEnded: 1},
+		// Below the threshold but above half of it keeps the episode going
+		{name: "hysteresis", pressure: []float64{50, 15, 15, 15, 15}, wantActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewContentionDetector(DefaultContentionConfig())
+			ended := 0
+			var now time.Time
+			for i, cpu := range tt.pressure {
+				now = start.Add(time.Duration(i) * 10 * time.Second)
+				ended += len(d.Observe(map[string]float64{ContentionCPU: cpu}, nil, now))
+			}
+
+			if ended != tt.wantEnded {
+				t.Errorf("ended %d episodes, want %d", ended, tt.wantEnded)
+			}
+			if active := len(d.Active(now)) > 0; active != tt.wantActive {
+				t.Errorf("active %v, want %v", active, tt.wantActive)
+			}
+			if got := len(d.Recent(start, now)); got != tt.wantEnded {
+				t.Errorf("recent %d, want %d", got, tt.wantEnded)
+			}
+		})
+	}
+}
+
+func TestContentionDetector_Attribution(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewContentionDetector(DefaultContentionConfig())
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * 10 * time.Second) }
+
+	// Quiet period: baselines of batch 10%, web 50%, idle 2%
+	quiet := []ContentionSample{
+		{ID: "batch", Name: "batch", CPUPercent: 10},
+		{ID: "web", Name: "web", CPUPercent: 50},
+		{ID: "idle", Name: "idle", CPUPercent: 2},
+		{ID: "api", Name: "api", CPUPercent: 20},
+	}
+	for i := 0; i < 5; i++ {
+		d.Observe(map[string]float64{ContentionCPU: 1}, quiet, at(i))
+	}
+
+	busy := []ContentionSample{
+		{ID: "batch", Name: "batch", CPUPercent: 300},
+		{ID: "web", Name: "web", CPUPercent: 20},  // dropped by 60%
+		{ID: "idle", Name: "idle", CPUPercent: 0}, // dropped, but below the baseline floor
+		{ID: "api", Name: "api", CPUPercent: 20, ThrottledRatio: 0.25},
+		{ID: "new", Name: "new", CPUPercent: 60}, // no baseline
+	}
+	for i := 5; i < 10; i++ {
+		d.Observe(map[string]float64{ContentionCPU: 40 + float64(i)}, busy, at(i))
+	}
+	ended := d.Observe(map[string]float64{ContentionCPU: 2}, quiet, at(10))
+	if len(ended) != 1 {
+		t.Fatalf("ended %d episodes, want 1", len(ended))
+	}
+	episode := ended[0]
+
+	if episode.Resource != ContentionCPU || episode.PeakPressure != 49 || episode.DurationSeconds != 50 {
+		t.Errorf("episode %+v", episode)
+	}
+	if len(episode.Contributors) != 4 || episode.Contributors[0].ID != "batch" || math.Abs(episode.Contributors[0].Share-0.75) > 1e-9 {
+		t.Errorf("contributors %+v, want batch first with 75%%", episode.Contributors)
+	}
+
+	want := map[string]string{"api": "throttled", "web": "usage_drop"}
+	if len(episode.Victims) != len(want) {
+		t.Fatalf("victims %+v, want %v", episode.Victims, want)
+	}
+	for _, victim := range episode.Victims {
+		if want[victim.ID] != victim.Reason {
+			t.Errorf("victim %s: reason %q, want %q", victim.ID, victim.Reason, want[victim.ID])
+		}
+	}
+
+	if got := d.Recent(at(20), at(30)); len(got) != 0 {
+		t.Errorf("episode outside the range reported: %+v", got)
+	}
+}
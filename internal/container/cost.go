@@ -0,0 +1,123 @@
+package container
+
+import (
+	"math"
+	"sort"
+)
+
+// CostRates are the hourly prices of host capacity
+type CostRates struct {
+	CPUHourly      float64 `json:"cpu_hourly"`       // per vCPU
+	MemoryGBHourly float64 `json:"memory_gb_hourly"` // per GB of RAM
+}
+
+// HostCostRates derives per-vCPU and per-GB rates. Explicit unit rates win; otherwise
+// the hourly host cost is split evenly between the host's CPUs and its memory.
+func HostCostRates(hostHourly, cpuHourly, memoryGBHourly float64, cores int, memoryGB float64) CostRates {
+	if cpuHourly > 0 || memoryGBHourly > 0 {
+		return CostRates{CPUHourly: cpuHourly, MemoryGBHourly: memoryGBHourly}
+	}
+
+	var rates CostRates
+	if cores > 0 {
+		rates.CPUHourly = hostHourly / 2 / float64(cores)
+	}
+	if memoryGB > 0 {
+		rates.MemoryGBHourly = hostHourly / 2 / memoryGB
+	}
+	return rates
+}
+
+// CostUsage is the CPU and memory attributed to one container over a period
+type CostUsage struct {
+	Group    string
+	CPUCores float64 // average cores used, or cores reserved
+	MemoryGB float64 // average memory used, or memory reserved
+	Presence float64 // share of the period the container existed, 0 to 1
+}
+
+// CostLine holds the cost attributed to one group
+type CostLine struct {
+	Group         string  `json:"group"`
+	Containers    int     `json:"containers"`
+	CPUCoreHours  float64 `json:"cpu_core_hours"`
+	MemoryGBHours float64 `json:"memory_gb_hours"`
+	CPUCost       float64 `json:"cpu_cost"`
+	MemoryCost    float64 `json:"memory_cost"`
+	Cost          float64 `json:"cost"`
+	Share         float64 `json:"share"` // of the total host cost
+}
+
+// CostAllocation splits the host cost of a period across groups
+type CostAllocation struct {
+	TotalCost      float64    `json:"total_cost"`
+	AllocatedCost  float64    `json:"allocated_cost"`
+	IdleCost       float64    `json:"idle_cost"`
+	IdleCPUCost    float64    `json:"idle_cpu_cost"`
+	IdleMemoryCost float64    `json:"idle_memory_cost"`
+	Overcommitted  bool       `json:"overcommitted"` // attributed capacity exceeds the host's
+	Groups         []CostLine `json:"groups"`
+}
+
+// AllocateCosts attributes the cost of the host's CPUs and memory over a period of
+// hours to groups of containers. Capacity nobody used (or reserved) is idle cost.
+// When attributed capacity exceeds the host's, as overcommitted reservations do,
+// it is scaled down so groups never add up to more than the host costs.
+func AllocateCosts(usages []CostUsage, rates CostRates, cores int, memoryGB, hours float64) CostAllocation {
+	totalCPUCost := float64(cores) * hours * rates.CPUHourly
+	totalMemoryCost := memoryGB * hours * rates.MemoryGBHourly
+
+	byGroup := make(map[string]*CostLine)
+	var cpuHours, memoryHours float64
+	for _, usage := range usages {
+		line, exists := byGroup[usage.Group]
+		if !exists {
+			line = &CostLine{Group: usage.Group}
+			byGroup[usage.Group] = line
+		}
+		line.Containers++
+		line.CPUCoreHours += usage.CPUCores * usage.Presence * hours
+		line.MemoryGBHours += usage.MemoryGB * usage.Presence * hours
+		cpuHours += usage.CPUCores * usage.Presence * hours
+		memoryHours += usage.MemoryGB * usage.Presence * hours
+	}
+
+	allocation := CostAllocation{TotalCost: totalCPUCost + totalMemoryCost, Groups: []CostLine{}}
+
+	cpuScale, memoryScale := 1.0, 1.0
+	if capacity := float64(cores) * hours; cpuHours > capacity && capacity > 0 {
+		cpuScale = capacity / cpuHours
+		allocation.Overcommitted = true
+	}
+	if capacity := memoryGB * hours; memoryHours > capacity && capacity > 0 {
+		memoryScale = capacity / memoryHours
+		allocation.Overcommitted = true
+	}
+
+	var allocatedCPU, allocatedMemory float64
+	for _, line := range byGroup {
+		line.CPUCost = line.CPUCoreHours * cpuScale * rates.CPUHourly
+		line.MemoryCost = line.MemoryGBHours * memoryScale * rates.MemoryGBHourly
+		line.Cost = line.CPUCost + line.MemoryCost
+		if allocation.TotalCost > 0 {
+			line.Share = line.Cost / allocation.TotalCost
+		}
+		allocatedCPU += line.CPUCost
+		allocatedMemory += line.MemoryCost
+		allocation.Groups = append(allocation.Groups, *line)
+	}
+
+	sort.Slice(allocation.Groups, func(i, j int) bool {
+		if allocation.Groups[i].Cost != allocation.Groups[j].Cost {
+			return allocation.Groups[i].Cost > allocation.Groups[j].Cost
+		}
+		return allocation.Groups[i].Group < allocation.Groups[j].Group
+	})
+
+	allocation.AllocatedCost = allocatedCPU + allocatedMemory
+	allocation.IdleCPUCost = math.Max(totalCPUCost-allocatedCPU, 0)
+	allocation.IdleMemoryCost = math.Max(totalMemoryCost-allocatedMemory, 0)
+	allocation.IdleCost = allocation.IdleCPUCost + allocation.IdleMemoryCost
+
+	return allocation
+}
@@ -0,0 +1,119 @@
+package container
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHostCostRates(t *testing.T) {
+	tests := []struct {
+		name                string
+		host, cpu, memory   float64
+		cores               int
+		memoryGB            float64
+		wantCPU, wantMemory float64
+	}{
+		{"split host cost", 8, 0, 0, 4, 16, 1, 0.25},
+		{"explicit rates win", 8, 0.5, 0, 4, 16, 0.5, 0},
+		{"unknown capacity", 8, 0, 0, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		rates := HostCostRates(tt.host, tt.cpu, tt.memory, tt.cores, tt.memoryGB)
+		if rates.CPUHourly != tt.wantCPU || rates.MemoryGBHourly != tt.wantMemory {
+			t.Errorf("%s: got %+v, want cpu %v memory %v", tt.name, rates, tt.wantCPU, tt.wantMemory)
+		}
+	}
+}
+
+func TestAllocateCosts(t *testing.T) {
+	// A 4-core, 16 GB host at 1 per core-hour and 0.25 per GB-hour costs 8 an hour
+	rates := CostRates{CPUHourly: 1, MemoryGBHourly: 0.25}
+
+	tests := []struct {
+		name           string
+		usages         []CostUsage
+		hours          float64
+		wantTotal      float64
+		wantIdle       float64
+		wantIdleCPU    float64
+		wantOvercommit bool
+		wantGroups     map[string]float64 // cost per group
+	}{
+		{
+			name:        "no containers",
+			hours:       10,
+			wantTotal:   80,
+			wantIdle:    80,
+			wantIdleCPU: 40,
+			wantGroups:  map[string]float64{},
+		},
+		{
+			name:       "zero hours",
+			usages:     []CostUsage{{Group: "a", CPUCores: 1, MemoryGB: 1, Presence: 1}},
+			wantGroups: map[string]float64{"a": 0},
+		},
+		{
+			name: "usage with idle split",
+			usages: []CostUsage{
+				{Group: "a", CPUCores: 1, MemoryGB: 4, Presence: 1},
+				{Group: "a", CPUCores: 1, MemoryGB: 0, Presence: 0.5}, // present half the period
+				{Group: "b", CPUCores: 0.5, MemoryGB: 2, Presence: 1},
+			},
+			hours:       10,
+			wantTotal:   80,
+			wantIdle:    80 - 25 - 10,
+			wantIdleCPU: 40 - 20,
+			wantGroups:  map[string]float64{"a": 15 + 10, "b": 5 + 5},
+		},
+		{
+			// 8 reserved cores on 4: every core-hour counts half; memory is not overcommitted
+			name: "overcommitted reservation",
+			usages: []CostUsage{
+				{Group: "a", CPUCores: 6, MemoryGB: 8, Presence: 1},
+				{Group: "b", CPUCores: 2, MemoryGB: 4, Presence: 1},
+			},
+			hours:          1,
+			wantTotal:      8,
+			wantIdle:       1, // the unreserved 4 GB
+			wantIdleCPU:    0,
+			wantOvercommit: true,
+			wantGroups:     map[string]float64{"a": 3 + 2, "b": 1 + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocation := AllocateCosts(tt.usages, rates, 4, 16, tt.hours)
+
+			if !near(allocation.TotalCost, tt.wantTotal) || !near(allocation.IdleCost, tt.wantIdle) || !near(allocation.IdleCPUCost, tt.wantIdleCPU) {
+				t.Errorf("total %v idle %v idle cpu %v, want %v, %v and %v", allocation.TotalCost, allocation.IdleCost, allocation.IdleCPUCost, tt.wantTotal, tt.wantIdle, tt.wantIdleCPU)
+			}
+			if allocation.Overcommitted != tt.wantOvercommit {
+				t.Errorf("overcommitted %v, want %v", allocation.Overcommitted, tt.wantOvercommit)
+			}
+			if !near(allocation.AllocatedCost+allocation.IdleCost, allocation.TotalCost) {
+				t.Errorf("allocated %v and idle %v do not add up to %v", allocation.AllocatedCost, allocation.IdleCost, allocation.TotalCost)
+			}
+
+			if len(allocation.Groups) != len(tt.wantGroups) {
+				t.Fatalf("groups %+v, want %v", allocation.Groups, tt.wantGroups)
+			}
+			for i, line := range allocation.Groups {
+				if !near(line.Cost, tt.wantGroups[line.Group]) {
+					t.Errorf("group %s: cost %v, want %v", line.Group, line.Cost, tt.wantGroups[line.Group])
+				}
+				if i > 0 && line.Cost > allocation.Groups[i-1].Cost {
+					t.Errorf("groups not sorted by cost: %+v", allocation.Groups)
+				}
+				if allocation.TotalCost > 0 && !near(line.Share, line.Cost/allocation.TotalCost) {
+					t.Errorf("group %s: share %v", line.Group, line.Share)
+				}
+			}
+		})
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,245 @@
+package container
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Docker's restart manager starts at 100ms and doubles the delay on every
+// consecutive restart, capping it at one minute
+const (
+	initialRestartBackoff = 100 * time.Millisecond
+	maxRestartBackoff     = time.Minute
+)
+
+// CrashLoopConfig holds the thresholds used to flag crash-looping containers
+type CrashLoopConfig struct {
+	RestartThreshold int           // restarts within RestartWindow that flag a container
+	RestartWindow    time.Duration // sliding window for restarts
+	FlapThreshold    int           // healthy/unhealthy transitions within FlapWindow that flag a container
+	FlapWindow       time.Duration // sliding window for health flaps
+}
+
+// DefaultCrashLoopConfig returns the default crash-loop thresholds
+func DefaultCrashLoopConfig() CrashLoopConfig {
+	return CrashLoopConfig{
+		RestartThreshold: 3,
+		RestartWindow:    5 * time.Minute,
+		FlapThreshold:    3,
+		FlapWindow:       10 * time.Minute,
+	}
+}
+
+// CrashLoopInfo describes a container flagged as crash-looping
+type CrashLoopInfo struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Reason         string    `json:"reason"` // "restarts", "health_flapping"
+	Restarts       int       `json:"restarts"`
+	HealthFlaps    int       `json:"health_flaps"`
+	WindowSeconds  int64     `json:"window_seconds"`
+	State          string    `json:"state"`
+	InBackoff      bool      `json:"in_backoff"`
+	BackoffSeconds float64   `json:"backoff_seconds"`
+	LastRestart    time.Time `json:"last_restart,omitempty"`
+	FlaggedSince   time.Time `json:"flagged_since"`
+}
+
+// CrashLoopDetector flags containers that restart or flap too often within a time window.
+// Restarts are taken from RestartCount deltas between samples and from restart events
+// in the history store; whichever source saw more restarts wins.
+type CrashLoopDetector struct {
+	config     CrashLoopConfig
+	mu         sync.Mutex
+	containers map[string]*crashLoopState
+	flagged    []CrashLoopInfo // result of the latest evaluation
+}
+
+type crashLoopState struct {
+	name             string
+	state            string
+	lastRestartCount int
+	sampled          bool
+	sampleRestarts   []time.Time
+	eventRestarts    []time.Time
+	healthFlaps      []time.Time
+	lastHealth       string
+	flaggedSince     time.Time
+}
+
+// NewCrashLoopDetector creates a detector with the given thresholds
+func NewCrashLoopDetector(config CrashLoopConfig) *CrashLoopDetector {
+	return &CrashLoopDetector{
+		config:     config,
+		containers: make(map[string]*crashLoopState),
+	}
+}
+
+// Config returns the thresholds used by the detector
+func (d *CrashLoopDetector) Config() CrashLoopConfig {
+	return d.config
+}
+
+// Observe records RestartCount deltas from a fresh sample of containers
+func (d *CrashLoopDetector) Observe(containers []ContainerData, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, c := range containers {
+		state := d.stateFor(c.ID, c.Name)
+		state.state = c.State
+
+		if state.sampled && c.RestartCount > state.lastRestartCount {
+			for i := 0; i < c.RestartCount-state.lastRestartCount; i++ {
+				state.sampleRestarts = append(state.sampleRestarts, now)
+			}
+		}
+		state.lastRestartCount = c.RestartCount
+		state.sampled = true
+	}
+}
+
+// ObserveRestarts replaces the restart timestamps known from the history store
+func (d *CrashLoopDetector) ObserveRestarts(id, name string, timestamps []time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.stateFor(id, name)
+	state.eventRestarts = append([]time.Time(nil), timestamps...)
+}
+
+// ObserveHealth records a health status report; only healthy/unhealthy transitions count as flaps
+func (d *CrashLoopDetector) ObserveHealth(id, name, status string, timestamp time.Time) {
+	if status != "healthy" && status != "unhealthy" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.stateFor(id, name)
+	if state.lastHealth != "" && state.lastHealth != status {
+		state.healthFlaps = append(state.healthFlaps, timestamp)
+	}
+	state.lastHealth = status
+}
+
+// Forget drops all state for a removed container
+func (d *CrashLoopDetector) Forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.containers, id)
+}
+
+// Evaluate returns the containers currently crash-looping, along with the ones
+// that became flagged and the ones that recovered since the previous evaluation
+func (d *CrashLoopDetector) Evaluate(now time.Time) (flagged, started, resolved []CrashLoopInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, state := range d.containers {
+		state.sampleRestarts = pruneBefore(state.sampleRestarts, now.Add(-d.config.RestartWindow))
+		state.eventRestarts = pruneBefore(state.eventRestarts, now.Add(-d.config.RestartWindow))
+		state.healthFlaps = pruneBefore(state.healthFlaps, now.Add(-d.config.FlapWindow))
+
+		restarts := state.sampleRestarts
+		if len(state.eventRestarts) > len(restarts) {
+			restarts = state.eventRestarts
+		}
+
+		info := CrashLoopInfo{
+			ID:          id,
+			Name:        state.name,
+			Restarts:    len(restarts),
+			HealthFlaps: len(state.healthFlaps),
+			State:       state.state,
+			InBackoff:   state.state == "restarting",
+		}
+		if len(restarts) > 0 {
+			info.LastRestart = restarts[len(restarts)-1]
+			info.BackoffSeconds = RestartBackoff(len(restarts)).Seconds()
+		}
+
+		switch {
+		case info.Restarts >= d.config.RestartThreshold:
+			info.Reason = "restarts"
+			info.WindowSeconds = int64(d.config.RestartWindow.Seconds())
+		case info.HealthFlaps >= d.config.FlapThreshold:
+			info.Reason = "health_flapping"
+			info.WindowSeconds = int64(d.config.FlapWindow.Seconds())
+		default:
+			if !state.flaggedSince.IsZero() {
+				state.flaggedSince = time.Time{}
+				resolved = append(resolved, info)
+			}
+			continue
+		}
+
+		if state.flaggedSince.IsZero() {
+			state.flaggedSince = now
+			info.FlaggedSince = now
+			started = append(started, info)
+		} else {
+			info.FlaggedSince = state.flaggedSince
+		}
+		flagged = append(flagged, info)
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].Restarts != flagged[j].Restarts {
+			return flagged[i].Restarts > flagged[j].Restarts
+		}
+		return flagged[i].ID < flagged[j].ID
+	})
+
+	d.flagged = flagged
+
+	return flagged, started, resolved
+}
+
+// Flagged returns the containers flagged by the latest evaluation
+func (d *CrashLoopDetector) Flagged() []CrashLoopInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make([]CrashLoopInfo, len(d.flagged))
+	copy(result, d.flagged)
+	return result
+}
+
+// RestartBackoff estimates the delay Docker applies before the next restart
+// after the given number of consecutive restarts
+func RestartBackoff(consecutiveRestarts int) time.Duration {
+	backoff := initialRestartBackoff
+	for i := 1; i < consecutiveRestarts; i++ {
+		backoff *= 2
+		if backoff >= maxRestartBackoff {
+			return maxRestartBackoff
+		}
+	}
+	return backoff
+}
+
+// stateFor returns the tracked state for a container, creating it if needed.
+// Callers must hold d.mu.
+func (d *CrashLoopDetector) stateFor(id, name string) *crashLoopState {
+	state, exists := d.containers[id]
+	if !exists {
+		state = &crashLoopState{}
+		d.containers[id] = state
+	}
+	if name != "" {
+		state.name = name
+	}
+	return state
+}
+
+// pruneBefore drops timestamps older than cutoff from a chronologically ordered slice
+func pruneBefore(timestamps []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(timestamps) && timestamps[i].Before(cutoff) {
+		i++
+	}
+	return timestamps[i:]
+}
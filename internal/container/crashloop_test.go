@@ -0,0 +1,161 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		restarts int
+		want     time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{10, 51200 * time.Millisecond},
+		{11, time.Minute}, // 102.4s is capped
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := RestartBackoff(tt.restarts); got != tt.want {
+			t.Errorf("RestartBackoff(%d) = %v, want %v", tt.restarts, got, tt.want)
+		}
+	}
+}
+
+func TestCrashLoopDetector_Evaluate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// sample is the restart count seen by one sampler tick
+	type sample struct {
+		at       int
+		restarts int
+	}
+
+	tests := []struct {
+		name         string
+		samples      []sample
+		events       []int // restart event offsets in seconds
+		health       []string
+		evaluateAt   int
+		wantReason   string
+		wantRestarts int
+	}{
+		{name: "no history", evaluateAt: 0},
+		{
+			name:       "single sample with a high restart count",
+			samples:    []sample{{0, 40}},
+			evaluateAt: 0,
+		},
+		{
+			name:         "below threshold",
+			samples:      []sample{{0, 1}, {10, 2}, {20, 3}},
+			evaluateAt:   20,
+			wantRestarts: 2,
+		},
+		{
+			name:         "threshold reached across samples",
+			samples:      []sample{{0, 1}, {10, 2}, {20, 4}},
+			evaluateAt:   20,
+			wantReason:   "restarts",
+			wantRestarts: 3,
+		},
+		{
+			name:         "restarts age out of the window",
+			samples:      []sample{{0, 0}, {10, 3}},
+			evaluateAt:   10 + 301,
+			wantRestarts: 0,
+		},
+		{
+			name:         "events seen more restarts than samples",
+			samples:      []sample{{0, 0}, {10, 1}},
+			events:       []int{2, 5, 9},
+			evaluateAt:   10,
+			wantReason:   "restarts",
+			wantRestarts: 3,
+		},
+		{
+			name:       "health flapping",
+			health:     []string{"healthy", "unhealthy", "healthy", "starting", "unhealthy"},
+			evaluateAt: 60,
+			wantReason: "health_flapping",
+		},
+		{
+			name:       "repeated health status is not a flap",
+			health:     []string{"healthy", "healthy", "unhealthy", "unhealthy", "unhealthy"},
+			evaluateAt: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewCrashLoopDetector(DefaultCrashLoopConfig())
+			for _, s := range tt.samples {
+				d.Observe([]ContainerData{{ID: "web", Name: "web", State: "running", RestartCount: s.restarts}}, at(s.at))
+			}
+			if tt.events != nil {
+				var timestamps []time.Time
+				for _, offset := range tt.events {
+					timestamps = append(timestamps, at(offset))
+				}
+				d.ObserveRestarts("web", "web", timestamps)
+			}
+			for i, status := range tt.health {
+				d.ObserveHealth("web", "web", status, at(i*10))
+			}
+
+			flagged, started, _ := d.Evaluate(at(tt.evaluateAt))
+			if tt.wantReason == "" {
+				if len(flagged) != 0 {
+					t.Fatalf("flagged %+v, want none", flagged)
+				}
+				return
+			}
+			if len(flagged) != 1 || len(started) != 1 {
+				t.Fatalf("flagged %+v, started %+v, want web", flagged, started)
+			}
+			if flagged[0].Reason != tt.wantReason {
+				t.Errorf("reason %q, want %q", flagged[0].Reason, tt.wantReason)
+			}
+			if tt.wantRestarts > 0 && flagged[0].Restarts != tt.wantRestarts {
+				t.Errorf("restarts %d, want %d", flagged[0].Restarts, tt.wantRestarts)
+			}
+		})
+	}
+}
+
+func TestCrashLoopDetector_Resolve(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewCrashLoopDetector(DefaultCrashLoopConfig())
+
+	d.Observe([]ContainerData{{ID: "web", State: "running", RestartCount: 0}}, start)
+	d.Observe([]ContainerData{{ID: "web", State: "restarting", RestartCount: 3}}, start.Add(10*time.Second))
+
+	flagged, started, resolved := d.Evaluate(start.Add(10 * time.Second))
+	if len(flagged) != 1 || len(started) != 1 || len(resolved) != 0 || !flagged[0].InBackoff {
+		t.Fatalf("first evaluation: flagged %+v, started %+v, resolved %+v", flagged, started, resolved)
+	}
+
+	// Still flagged: not reported as started again
+	_, started, _ = d.Evaluate(start.Add(20 * time.Second))
+	if len(started) != 0 {
+		t.Errorf("started again: %+v", started)
+	}
+
+	flagged, _, resolved = d.Evaluate(start.Add(10*time.Second + 5*time.Minute + time.Second))
+	if len(flagged) != 0 || len(resolved) != 1 || resolved[0].ID != "web" {
+		t.Errorf("after the window: flagged %+v, resolved %+v", flagged, resolved)
+	}
+	if got := d.Flagged(); len(got) != 0 {
+		t.Errorf("Flagged() = %+v", got)
+	}
+
+	d.Forget("web")
+	if flagged, _, resolved := d.Evaluate(start.Add(time.Hour)); len(flagged) != 0 || len(resolved) != 0 {
+		t.Errorf("forgotten container still tracked")
+	}
+}
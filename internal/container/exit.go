@@ -0,0 +1,72 @@
+package container
+
+import (
+	"fmt"
+)
+
+// ExitReason describes why a container stopped running
+type ExitReason string
+
+const (
+	ExitReasonOOMKilled     ExitReason = "oom_killed"
+	ExitReasonSignal        ExitReason = "signal"
+	ExitReasonAppError      ExitReason = "app_error"
+	ExitReasonCleanExit     ExitReason = "clean_exit"
+	ExitReasonDaemonRestart ExitReason = "daemon_restart"
+)
+
+// signalNames maps the signals Docker commonly uses to stop containers
+var signalNames = map[int]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	6:  "SIGABRT",
+	9:  "SIGKILL",
+	11: "SIGSEGV",
+	13: "SIGPIPE",
+	15: "SIGTERM",
+}
+
+// ExitClassification is the result of classifying a container death
+type ExitClassification struct {
+	Reason   ExitReason `json:"reason"`
+	ExitCode int        `json:"exit_code"`
+	Signal   string     `json:"signal,omitempty"`
+}
+
+// ClassifyExit determines why a container died from its exit code and OOM flag.
+// Exit codes above 128 follow the shell convention of 128 + signal number.
+func ClassifyExit(exitCode int, oomKilled bool) ExitClassification {
+	result := ExitClassification{ExitCode: exitCode}
+
+	switch {
+	case oomKilled:
+		result.Reason = ExitReasonOOMKilled
+		if exitCode > 128 {
+			result.Signal = SignalName(exitCode - 128)
+		}
+	case exitCode == 0:
+		result.Reason = ExitReasonCleanExit
+	case exitCode > 128 && exitCode <= 128+64:
+		result.Reason = ExitReasonSignal
+		result.Signal = SignalName(exitCode - 128)
+	default:
+		result.Reason = ExitReasonAppError
+	}
+
+	return result
+}
+
+// SignalName returns the conventional name of a signal number
+func SignalName(signal int) string {
+	if name, ok := signalNames[signal]; ok {
+		return name
+	}
+	return fmt.Sprintf("SIG%d", signal)
+}
+
+// IsCrash reports whether the exit reason counts as a crash.
+// Clean exits and deaths caused by the daemon going away are not the container's fault.
+func (r ExitReason) IsCrash() bool {
+	return r != ExitReasonCleanExit && r != ExitReasonDaemonRestart
+}
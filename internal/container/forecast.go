@@ -0,0 +1,113 @@
+package container
+
+import (
+	"math"
+	"time"
+)
+
+// Forecasts need enough history for the trend to mean anything
+const (
+	minForecastSamples = 10
+	minForecastSpan    = 10 * time.Minute
+)
+
+// Forecast predicts when a growing resource reaches its limit
+type Forecast struct {
+	Kind          string     `json:"kind"` // "memory", "disk"
+	ID            string     `json:"id,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Path          string     `json:"path,omitempty"`
+	Current       float64    `json:"current"`         // in MB
+	Limit         float64    `json:"limit"`           // in MB
+	GrowthPerHour float64    `json:"growth_per_hour"` // in MB, from a least-squares fit
+	Fit           float64    `json:"fit"`             // r², 1 means a perfectly linear trend
+	Samples       int        `json:"samples"`
+	ExhaustionAt  *time.Time `json:"exhaustion_at,omitempty"`
+	SecondsLeft   *float64   `json:"seconds_left,omitempty"`
+	Alerting      bool       `json:"alerting"`
+}
+
+// ForecastExhaustion fits a linear trend to a chronological series and predicts when
+// it crosses the limit. A forecast is alerting when the crossing falls within the horizon.
+// Without enough history, or with a flat or shrinking trend, no exhaustion time is set.
+func ForecastExhaustion(times []time.Time, values []float64, limit float64, now time.Time, horizon time.Duration) Forecast {
+	forecast := Forecast{Limit: limit, Samples: len(values)}
+	if len(values) == 0 {
+		return forecast
+	}
+	forecast.Current = values[len(values)-1]
+
+	// Already at the limit
+	if limit > 0 && forecast.Current >= limit {
+		forecast.setExhaustion(now, now, horizon)
+		return forecast
+	}
+
+	if len(values) < minForecastSamples || times[len(times)-1].Sub(times[0]) < minForecastSpan {
+		return forecast
+	}
+
+	// Fit against hours since the first sample to keep the numbers small
+	xs := make([]float64, len(times))
+	for i, t := range times {
+		xs[i] = t.Sub(times[0]).Hours()
+	}
+	slope, intercept, r2 := LinearFit(xs, values)
+	forecast.GrowthPerHour = slope
+	forecast.Fit = r2
+
+	if slope <= 0 || limit <= 0 {
+		return forecast
+	}
+
+	hours := (limit - intercept) / slope
+	exhaustion := times[0].Add(time.Duration(hours * float64(time.Hour)))
+	if exhaustion.Before(now) {
+		exhaustion = now
+	}
+	forecast.setExhaustion(exhaustion, now, horizon)
+
+	return forecast
+}
+
+// setExhaustion records the predicted crossing and whether it is within the horizon
+func (f *Forecast) setExhaustion(exhaustion, now time.Time, horizon time.Duration) {
+	secondsLeft := exhaustion.Sub(now).Seconds()
+	f.ExhaustionAt = &exhaustion
+	f.SecondsLeft = &secondsLeft
+	f.Alerting = exhaustion.Sub(now) <= horizon
+}
+
+// LinearFit computes the least-squares line through the points and its r²
+func LinearFit(xs, ys []float64) (slope, intercept, r2 float64) {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0, 0, 0
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, meanY, 0
+	}
+
+	slope = sxy / sxx
+	intercept = meanY - slope*meanX
+	if syy > 0 {
+		r2 = math.Min(sxy*sxy/(sxx*syy), 1)
+	}
+
+	return slope, intercept, r2
+}
@@ -0,0 +1,93 @@
+package container
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLinearFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		xs, ys        []float64
+		wantSlope     float64
+		wantIntercept float64
+		wantR2        float64
+	}{
+		{"empty", nil, nil, 0, 0, 0},
+		{"single point", []float64{3}, []float64{7}, 0, 7, 0},
+		{"same x", []float64{1, 1, 1}, []float64{2, 4, 6}, 0, 4, 0},
+		{"perfect line", []float64{0, 1, 2, 3}, []float64{1, 3, 5, 7}, 2, 1, 1},
+		{"flat", []float64{0, 1, 2}, []float64{5, 5, 5}, 0, 5, 0},
+		// Least squares through (0,0), (1,2), (2,1): slope 0.5, intercept 0.5, r² 0.25
+		{"noisy", []float64{0, 1, 2}, []float64{0, 2, 1}, 0.5, 0.5, 0.25},
+	}
+
+	for _, tt := range tests {
+		slope, intercept, r2 := LinearFit(tt.xs, tt.ys)
+		if math.Abs(slope-tt.wantSlope) > 1e-9 || math.Abs(intercept-tt.wantIntercept) > 1e-9 || math.Abs(r2-tt.wantR2) > 1e-9 {
+			t.Errorf("%s: got (%v, %v, %v), want (%v, %v, %v)", tt.name, slope, intercept, r2, tt.wantSlope, tt.wantIntercept, tt.wantR2)
+		}
+	}
+}
+
+func TestForecastExhaustion(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// series returns n samples one minute apart, growing by step MB per minute from base
+	series := func(n int, base, step float64) ([]time.Time, []float64) {
+		times := make([]time.Time, n)
+		values := make([]float64, n)
+		for i := range times {
+			times[i] = start.Add(time.Duration(i) * time.Minute)
+			values[i] = base + float64(i)*step
+		}
+		return times, values
+	}
+
+	tests := []struct {
+		name          string
+		samples       int
+		base, step    float64
+		limit         float64
+		horizon       time.Duration
+		wantExhaust   bool
+		wantAlerting  bool
+		wantHoursLeft float64
+	}{
+		{name: "empty history", samples: 0, limit: 100, horizon: time.Hour},
+		{name: "single sample", samples: 1, base: 50, limit: 100, horizon: time.Hour},
+		{name: "single sample at the limit", samples: 1, base: 100, limit: 100, horizon: time.Hour, wantExhaust: true, wantAlerting: true},
+		{name: "too short a span", samples: 5, base: 10, step: 1, limit: 100, horizon: time.Hour},
+		{name: "flat", samples: 30, base: 50, limit: 100, horizon: time.Hour},
+		{name: "shrinking", samples: 30, base: 80, step: -1, limit: 100, horizon: time.Hour},
+		{name: "no limit", samples: 30, base: 10, step: 1, limit: 0, horizon: time.Hour},
+		// 39 MB after 29 minutes at 1 MB/min: the remaining 61 MB take 61 minutes
+		{name: "within the horizon", samples: 30, base: 10, step: 1, limit: 100, horizon: 2 * time.Hour, wantExhaust: true, wantAlerting: true, wantHoursLeft: 61.0 / 60},
+		{name: "beyond the horizon", samples: 30, base: 10, step: 1, limit: 100, horizon: 30 * time.Minute, wantExhaust: true, wantHoursLeft: 61.0 / 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, values := series(tt.samples, tt.base, tt.step)
+			now := start
+			if len(times) > 0 {
+				now = times[len(times)-1]
+			}
+
+			forecast := ForecastExhaustion(times, values, tt.limit, now, tt.horizon)
+			if forecast.Samples != tt.samples {
+				t.Errorf("samples %d, want %d", forecast.Samples, tt.samples)
+			}
+			if (forecast.ExhaustionAt != nil) != tt.wantExhaust {
+				t.Fatalf("exhaustion %v, want set: %v", forecast.ExhaustionAt, tt.wantExhaust)
+			}
+			if forecast.Alerting != tt.wantAlerting {
+				t.Errorf("alerting %v, want %v", forecast.Alerting, tt.wantAlerting)
+			}
+			if tt.wantHoursLeft > 0 && math.Abs(*forecast.SecondsLeft/3600-tt.wantHoursLeft) > 1e-6 {
+				t.Errorf("hours left %v, want %v", *forecast.SecondsLeft/3600, tt.wantHoursLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package container
+
+import (
+	"github.com/docker/docker/api/types"
+)
+
+// ExtractHealth converts Docker's HEALTHCHECK state into the UI model.
+// It returns nil for containers without a health check.
+func ExtractHealth(health *types.Health) *HealthInfo {
+	if health == nil || health.Status == "" || health.Status == types.NoHealthcheck {
+		return nil
+	}
+
+	info := &HealthInfo{
+		Status:        health.Status,
+		FailingStreak: health.FailingStreak,
+		Log:           make([]HealthProbe, 0, len(health.Log)),
+	}
+
+	for _, result := range health.Log {
+		if result == nil {
+			continue
+		}
+		info.Log = append(info.Log, HealthProbe{
+			Start:    result.Start,
+			End:      result.End,
+			ExitCode: result.ExitCode,
+			Output:   result.Output,
+		})
+	}
+
+	return info
+}
@@ -0,0 +1,308 @@
+package container
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// RedactedValue replaces the value of environment variables that look like secrets
+const RedactedValue = "********"
+
+// InspectView is a curated subset of a container's inspect data
+type InspectView struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	ImageID       string            `json:"image_id"`
+	Created       string            `json:"created"`
+	State         string            `json:"state"`
+	Command       []string          `json:"command"`
+	Entrypoint    []string          `json:"entrypoint"`
+	WorkingDir    string            `json:"working_dir"`
+	User          string            `json:"user"`
+	Env           []string          `json:"env"`
+	EnvRedacted   bool              `json:"env_redacted"`
+	Mounts        []MountInfo       `json:"mounts"`
+	Ports         []PortBinding     `json:"ports"`
+	Networks      []NetworkInfo     `json:"networks"`
+	Labels        map[string]string `json:"labels"`
+	RestartPolicy RestartPolicy     `json:"restart_policy"`
+	Resources     ResourceLimits    `json:"resources"`
+	Security      SecurityOptions   `json:"security"`
+	LogDriver     string            `json:"log_driver"`
+	LogOptions    map[string]string `json:"log_options,omitempty"`
+
+	LogOptionsRedacted bool `json:"log_options_redacted,omitempty"`
+	CommandRedacted    bool `json:"command_redacted,omitempty"` // command or entrypoint
+	LabelsRedacted     bool `json:"labels_redacted,omitempty"`
+}
+
+// MountInfo describes a volume or bind mount
+type MountInfo struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Mode        string `json:"mode,omitempty"`
+	ReadWrite   bool   `json:"read_write"`
+}
+
+// PortBinding describes a published container port
+type PortBinding struct {
+	ContainerPort string `json:"container_port"`
+	HostIP        string `json:"host_ip,omitempty"`
+	HostPort      string `json:"host_port,omitempty"`
+}
+
+// NetworkInfo describes a network the container is attached to
+type NetworkInfo struct {
+	Name       string   `json:"name"`
+	IPAddress  string   `json:"ip_address"`
+	Gateway    string   `json:"gateway"`
+	MacAddress string   `json:"mac_address"`
+	Aliases    []string `json:"aliases,omitempty"`
+}
+
+// RestartPolicy describes when Docker restarts the container
+type RestartPolicy struct {
+	Name              string `json:"name"`
+	MaximumRetryCount int    `json:"maximum_retry_count"`
+}
+
+// ResourceLimits holds the cgroup limits applied to the container
+type ResourceLimits struct {
+	Memory            int64  `json:"memory"`             // bytes, 0 = unlimited
+	MemoryReservation int64  `json:"memory_reservation"` // bytes
+	MemorySwap        int64  `json:"memory_swap"`        // bytes, -1 = unlimited
+	NanoCPUs          int64  `json:"nano_cpus"`
+	CPUShares         int64  `json:"cpu_shares"`
+	CPUQuota          int64  `json:"cpu_quota"`
+	CPUPeriod         int64  `json:"cpu_period"`
+	CpusetCpus        string `json:"cpuset_cpus,omitempty"`
+	PidsLimit         int64  `json:"pids_limit"` // 0 or -1 = unlimited
+}
+
+// SecurityOptions holds the isolation settings of the container
+type SecurityOptions struct {
+	Privileged      bool     `json:"privileged"`
+	ReadonlyRootfs  bool     `json:"readonly_rootfs"`
+	CapAdd          []string `json:"cap_add,omitempty"`
+	CapDrop         []string `json:"cap_drop,omitempty"`
+	SecurityOpt     []string `json:"security_opt,omitempty"`
+	AppArmorProfile string   `json:"apparmor_profile,omitempty"`
+	NetworkMode     string   `json:"network_mode"`
+	PidMode         string   `json:"pid_mode,omitempty"`
+	IpcMode         string   `json:"ipc_mode,omitempty"`
+	UsernsMode      string   `json:"userns_mode,omitempty"`
+}
+
+// BuildInspectView extracts the curated view from raw inspect data
+func BuildInspectView(info types.ContainerJSON) InspectView {
+	view := InspectView{
+		Mounts:   make([]MountInfo, 0, len(info.Mounts)),
+		Ports:    []PortBinding{},
+		Networks: []NetworkInfo{},
+		Labels:   map[string]string{},
+	}
+
+	if info.ContainerJSONBase != nil {
+		view.ID = info.ID
+		if len(view.ID) > 12 {
+			view.ID = view.ID[:12]
+		}
+		view.Name = strings.TrimPrefix(info.Name, "/")
+		view.ImageID = info.Image
+		view.Created = info.Created
+		view.Security.AppArmorProfile = info.AppArmorProfile
+		if info.State != nil {
+			view.State = info.State.Status
+		}
+	}
+
+	if info.Config != nil {
+		view.Image = info.Config.Image
+		view.Command = info.Config.Cmd
+		view.Entrypoint = info.Config.Entrypoint
+		view.WorkingDir = info.Config.WorkingDir
+		view.User = info.Config.User
+		view.Env = append([]string{}, info.Config.Env...)
+		if info.Config.Labels != nil {
+			view.Labels = info.Config.Labels
+		}
+	}
+
+	if info.ContainerJSONBase != nil && info.HostConfig != nil {
+		hc := info.HostConfig
+		view.RestartPolicy = RestartPolicy{
+			Name:              string(hc.RestartPolicy.Name),
+			MaximumRetryCount: hc.RestartPolicy.MaximumRetryCount,
+		}
+		view.Resources = ResourceLimits{
+			Memory:            hc.Memory,
+			MemoryReservation: hc.MemoryReservation,
+			MemorySwap:        hc.MemorySwap,
+			NanoCPUs:          hc.NanoCPUs,
+			CPUShares:         hc.CPUShares,
+			CPUQuota:          hc.CPUQuota,
+			CPUPeriod:         hc.CPUPeriod,
+			CpusetCpus:        hc.CpusetCpus,
+		}
+		if hc.PidsLimit != nil {
+			view.Resources.PidsLimit = *hc.PidsLimit
+		}
+		view.Security.Privileged = hc.Privileged
+		view.Security.ReadonlyRootfs = hc.ReadonlyRootfs
+		view.Security.CapAdd = hc.CapAdd
+		view.Security.CapDrop = hc.CapDrop
+		view.Security.SecurityOpt = hc.SecurityOpt
+		view.Security.NetworkMode = string(hc.NetworkMode)
+		view.Security.PidMode = string(hc.PidMode)
+		view.Security.IpcMode = string(hc.IpcMode)
+		view.Security.UsernsMode = string(hc.UsernsMode)
+		view.LogDriver = hc.LogConfig.Type
+		view.LogOptions = hc.LogConfig.Config
+	}
+
+	for _, m := range info.Mounts {
+		view.Mounts = append(view.Mounts, MountInfo{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			ReadWrite:   m.RW,
+		})
+	}
+
+	if info.NetworkSettings != nil {
+		for port, bindings := range info.NetworkSettings.Ports {
+			if len(bindings) == 0 {
+				view.Ports = append(view.Ports, PortBinding{ContainerPort: string(port)})
+				continue
+			}
+			for _, binding := range bindings {
+				view.Ports = append(view.Ports, PortBinding{
+					ContainerPort: string(port),
+					HostIP:        binding.HostIP,
+					HostPort:      binding.HostPort,
+				})
+			}
+		}
+		sort.Slice(view.Ports, func(i, j int) bool {
+			return view.Ports[i].ContainerPort < view.Ports[j].ContainerPort
+		})
+
+		for name, endpoint := range info.NetworkSettings.Networks {
+			if endpoint == nil {
+				continue
+			}
+			view.Networks = append(view.Networks, NetworkInfo{
+				Name:       name,
+				IPAddress:  endpoint.IPAddress,
+				Gateway:    endpoint.Gateway,
+				MacAddress: endpoint.MacAddress,
+				Aliases:    endpoint.Aliases,
+			})
+		}
+		sort.Slice(view.Networks, func(i, j int) bool {
+			return view.Networks[i].Name < view.Networks[j].Name
+		})
+	}
+
+	return view
+}
+
+// RedactEnv masks the values of KEY=VALUE entries whose key contains any of the
+// patterns (case-insensitive). It reports whether anything was redacted.
+func RedactEnv(env []string, patterns []string) ([]string, bool) {
+	result := make([]string, len(env))
+	redacted := false
+
+	for i, entry := range env {
+		result[i] = entry
+
+		key, _, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		if IsSecretName(key, patterns) {
+			result[i] = key + "=" + RedactedValue
+			redacted = true
+		}
+	}
+
+	return result, redacted
+}
+
+// RedactOptions returns a copy of key/value options, such as log driver options,
+// with the values of secret-looking keys masked. It reports whether anything was redacted.
+func RedactOptions(options map[string]string, patterns []string) (map[string]string, bool) {
+	if options == nil {
+		return nil, false
+	}
+
+	result := make(map[string]string, len(options))
+	redacted := false
+	for key, value := range options {
+		result[key] = value
+		if IsSecretName(key, patterns) {
+			result[key] = RedactedValue
+			redacted = true
+		}
+	}
+
+	return result, redacted
+}
+
+// RedactArgs masks the values of command-line arguments whose name contains any of
+// the patterns: "--flag=value", "--flag value" and "KEY=value". It reports whether
+// anything was redacted.
+func RedactArgs(args []string, patterns []string) ([]string, bool) {
+	if args == nil {
+		return nil, false
+	}
+
+	result := make([]string, len(args))
+	redacted := false
+	maskNext := false
+
+	for i, arg := range args {
+		result[i] = arg
+
+		if maskNext {
+			maskNext = false
+			if !strings.HasPrefix(arg, "-") {
+				result[i] = RedactedValue
+				redacted = true
+				continue
+			}
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "" || strings.ContainsAny(name, " \t") || !IsSecretName(name, patterns) {
+			continue
+		}
+		switch {
+		case hasValue:
+			result[i] = arg[:strings.Index(arg, "=")+1] + RedactedValue
+			redacted = true
+		case strings.HasPrefix(arg, "-"):
+			maskNext = true
+		}
+	}
+
+	return result, redacted
+}
+
+// IsSecretName reports whether a variable name matches any secret pattern
+func IsSecretName(name string, patterns []string) bool {
+	upper := strings.ToUpper(name)
+	for _, pattern := range patterns {
+		if pattern != "" && strings.Contains(upper, strings.ToUpper(pattern)) {
+			return true
+		}
+	}
+	return false
+}
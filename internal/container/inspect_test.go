@@ -0,0 +1,32 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	patterns := []string{"PASSWORD", "TOKEN"}
+
+	tests := []struct {
+		args         []string
+		want         []string
+		wantRedacted bool
+	}{
+		{args: nil, want: nil},
+		{args: []string{"nginx", "-g", "daemon off;"}, want: []string{"nginx", "-g", "daemon off;"}},
+		{args: []string{"app", "--password=hunter2"}, want: []string{"app", "--password=" + RedactedValue}, wantRedacted: true},
+		{args: []string{"app", "--api-token", "abc", "--port", "80"}, want: []string{"app", "--api-token", RedactedValue, "--port", "80"}, wantRedacted: true},
+		{args: []string{"app", "-password", "-v"}, want: []string{"app", "-password", "-v"}},
+		{args: []string{"env", "DB_PASSWORD=hunter2", "app"}, want: []string{"env", "DB_PASSWORD=" + RedactedValue, "app"}, wantRedacted: true},
+		{args: []string{"sh", "-c", "echo token=x"}, want: []string{"sh", "-c", "echo token=x"}},
+		{args: []string{"app", "--token"}, want: []string{"app", "--token"}},
+	}
+
+	for _, tt := range tests {
+		got, redacted := RedactArgs(tt.args, patterns)
+		if !reflect.DeepEqual(got, tt.want) || redacted != tt.wantRedacted {
+			t.Errorf("RedactArgs(%q) = %q, %v, want %q, %v", tt.args, got, redacted, tt.want, tt.wantRedacted)
+		}
+	}
+}
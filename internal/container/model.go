@@ -1,40 +1,86 @@
 package container
 
+import (
+	"time"
+)
+
 // ContainerData holds the processed stats for the UI
 type ContainerData struct {
-	ID           string  `json:"id"`
-	Name         string  `json:"name"`
-	Image        string  `json:"image"`
-	State        string  `json:"state"`
-	Status       string  `json:"status"`
-	CPUPercent   float64 `json:"cpu_percent"`
-	MemUsage     float64 `json:"mem_usage"` // in MB
-	MemLimit     float64 `json:"mem_limit"` // in MB
-	MemPercent   float64 `json:"mem_percent"`
-	NetInput     float64 `json:"net_input"`  // KB
-	NetOutput    float64 `json:"net_output"` // KB
-	BlockInput   float64 `json:"block_input"` // KB
-	BlockOutput  float64 `json:"block_output"` // KB
-	Created      int64   `json:"created"`
-	RestartCount int     `json:"restart_count"`
-	Uptime       int64   `json:"uptime"` // in seconds
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Image           string            `json:"image"`
+	State           string            `json:"state"`
+	Status          string            `json:"status"`
+	CPUPercent      float64           `json:"cpu_percent"`
+	MemUsage        float64           `json:"mem_usage"` // in MB
+	MemLimit        float64           `json:"mem_limit"` // in MB
+	MemPercent      float64           `json:"mem_percent"`
+	NetInput        float64           `json:"net_input"`    // KB
+	NetOutput       float64           `json:"net_output"`   // KB
+	BlockInput      float64           `json:"block_input"`  // KB
+	BlockOutput     float64           `json:"block_output"` // KB
+	Created         int64             `json:"created"`
+	RestartCount    int               `json:"restart_count"`
+	Uptime          int64             `json:"uptime"` // in seconds
+	Health          *HealthInfo       `json:"health,omitempty"`
+	Project         string            `json:"project,omitempty"`
+	Service         string            `json:"service,omitempty"`
+	ContainerNumber int               `json:"container_number,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Host            string            `json:"host,omitempty"`
+}
+
+// HealthInfo holds the Docker HEALTHCHECK state of a container
+type HealthInfo struct {
+	Status        string        `json:"status"` // "starting", "healthy", "unhealthy"
+	FailingStreak int           `json:"failing_streak"`
+	Log           []HealthProbe `json:"log"`
+}
+
+// HealthProbe holds the result of a single health check run
+type HealthProbe struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exit_code"`
+	Output   string    `json:"output"`
+}
+
+// GroupedMetrics holds the aggregate statistics of one group of containers
+type GroupedMetrics struct {
+	Group   string           `json:"group"`
+	Metrics AggregateMetrics `json:"metrics"`
 }
 
 // AggregateMetrics holds system-wide aggregate statistics
 type AggregateMetrics struct {
-	TotalContainers       int     `json:"total_containers"`
-	RunningContainers     int     `json:"running_containers"`
-	StoppedContainers     int     `json:"stopped_containers"`
-	TotalCPUPercent       float64 `json:"total_cpu_percent"`
-	TotalMemUsage         float64 `json:"total_mem_usage"` // in MB
-	TotalMemLimit         float64 `json:"total_mem_limit"` // in MB
-	TotalNetInput         float64 `json:"total_net_input"`  // KB
-	TotalNetOutput        float64 `json:"total_net_output"` // KB
-	TotalBlockInput       float64 `json:"total_block_input"` // KB
-	TotalBlockOutput      float64 `json:"total_block_output"` // KB
-	AverageCPUPercent     float64 `json:"average_cpu_percent"`
-	AverageMemPercent     float64 `json:"average_mem_percent"`
+	TotalContainers        int                `json:"total_containers"`
+	RunningContainers      int                `json:"running_containers"`
+	StoppedContainers      int                `json:"stopped_containers"`
+	UnhealthyContainers    int                `json:"unhealthy_containers"`
+	TotalCPUPercent        float64            `json:"total_cpu_percent"`
+	TotalMemUsage          float64            `json:"total_mem_usage"`    // in MB
+	TotalMemLimit          float64            `json:"total_mem_limit"`    // in MB
+	TotalNetInput          float64            `json:"total_net_input"`    // KB
+	TotalNetOutput         float64            `json:"total_net_output"`   // KB
+	TotalBlockInput        float64            `json:"total_block_input"`  // KB
+	TotalBlockOutput       float64            `json:"total_block_output"` // KB
+	AverageCPUPercent      float64            `json:"average_cpu_percent"`
+	AverageMemPercent      float64            `json:"average_mem_percent"`
 	MostRestartedContainer *MostRestartedInfo `json:"most_restarted_container,omitempty"`
+	CrashLoopingContainers []CrashLoopInfo    `json:"crash_looping_containers,omitempty"`
+	Host                   *HostCapacity      `json:"host,omitempty"`
+}
+
+// HostCapacity expresses container totals as a share of the host's real capacity
+type HostCapacity struct {
+	CPUCores            int     `json:"cpu_cores"`
+	MemTotal            float64 `json:"mem_total"`           // in MB
+	CPUShare            float64 `json:"cpu_share_percent"`   // container CPU as a percent of all cores
+	MemShare            float64 `json:"mem_share_percent"`   // container memory as a percent of host RAM
+	CommittedMemLimit   float64 `json:"committed_mem_limit"` // sum of explicit limits, in MB
+	UnlimitedContainers int     `json:"unlimited_containers"`
+	MemOvercommitRatio  float64 `json:"mem_overcommit_ratio"` // committed limits / host RAM
+	Overcommitted       bool    `json:"overcommitted"`
 }
 
 // MostRestartedInfo holds information about the most restarted container
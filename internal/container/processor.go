@@ -49,22 +49,28 @@ func ProcessStats(c types.Container, stats *types.StatsJSON, restartCount int) C
 		name = c.Names[0][1:] // Remove leading slash
 	}
 
+	compose := ParseCompose(c.Labels)
+
 	return ContainerData{
-		ID:          c.ID[:12],
-		Name:        name,
-		Image:       c.Image,
-		State:       c.State,
-		Status:      c.Status,
-		CPUPercent:  cpuPercent,
-		MemUsage:    memUsage,
-		MemLimit:    memLimit,
-		MemPercent:  memPercent,
-		NetInput:    rx / 1024,
-		NetOutput:   tx / 1024,
-		BlockInput:  blkRead / 1024,
-		BlockOutput: blkWrite / 1024,
-		Created:     c.Created,
-		RestartCount: restartCount,
-		Uptime:      CalculateUptime(c.Created, c.State),
+		ID:              c.ID[:12],
+		Name:            name,
+		Image:           c.Image,
+		State:           c.State,
+		Status:          c.Status,
+		CPUPercent:      cpuPercent,
+		MemUsage:        memUsage,
+		MemLimit:        memLimit,
+		MemPercent:      memPercent,
+		NetInput:        rx / 1024,
+		NetOutput:       tx / 1024,
+		BlockInput:      blkRead / 1024,
+		BlockOutput:     blkWrite / 1024,
+		Created:         c.Created,
+		RestartCount:    restartCount,
+		Uptime:          CalculateUptime(c.Created, c.State),
+		Project:         compose.Project,
+		Service:         compose.Service,
+		ContainerNumber: compose.ContainerNumber,
+		Labels:          c.Labels,
 	}
 }
@@ -0,0 +1,187 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	dockercontainer "github.com/docker/docker/api/types/container"
+)
+
+// Docker rejects memory limits below 6MB and CPU periods or quotas outside these bounds
+const (
+	minMemoryLimit = 6 * 1024 * 1024
+	minCPUShares   = 2
+	minCPUPeriod   = 1000
+	maxCPUPeriod   = 1000000
+	minCPUQuota    = 1000
+)
+
+// restartPolicies are the restart policy names accepted by Docker
+var restartPolicies = map[string]bool{
+	"no":             true,
+	"always":         true,
+	"unless-stopped": true,
+	"on-failure":     true,
+}
+
+// ResourceUpdate is a partial change to a running container's limits; nil fields are left unchanged
+type ResourceUpdate struct {
+	Memory        *int64         `json:"memory,omitempty"`      // bytes
+	MemorySwap    *int64         `json:"memory_swap,omitempty"` // bytes of memory plus swap, -1 = unlimited
+	CPUShares     *int64         `json:"cpu_shares,omitempty"`
+	CPUQuota      *int64         `json:"cpu_quota,omitempty"`  // microseconds per period, -1 = unlimited
+	CPUPeriod     *int64         `json:"cpu_period,omitempty"` // microseconds
+	CpusetCpus    *string        `json:"cpuset_cpus,omitempty"`
+	PidsLimit     *int64         `json:"pids_limit,omitempty"` // -1 = unlimited
+	RestartPolicy *RestartPolicy `json:"restart_policy,omitempty"`
+}
+
+// Empty reports whether the update changes nothing
+func (u ResourceUpdate) Empty() bool {
+	return u.Memory == nil && u.MemorySwap == nil && u.CPUShares == nil && u.CPUQuota == nil &&
+		u.CPUPeriod == nil && u.CpusetCpus == nil && u.PidsLimit == nil && u.RestartPolicy == nil
+}
+
+// Validate checks the update against Docker's bounds, the container's current
+// limits and the capacity of the host (CPU count and memory in bytes)
+func (u ResourceUpdate) Validate(current ResourceLimits, hostCPUs int, hostMemory int64) error {
+	memory := current.Memory
+	if u.Memory != nil {
+		memory = *u.Memory
+		if memory < minMemoryLimit {
+			return fmt.Errorf("memory must be at least %d bytes", minMemoryLimit)
+		}
+		if hostMemory > 0 && memory > hostMemory {
+			return fmt.Errorf("memory %d exceeds host memory %d", memory, hostMemory)
+		}
+	}
+
+	swap := current.MemorySwap
+	if u.MemorySwap != nil {
+		swap = *u.MemorySwap
+		if swap < -1 {
+			return fmt.Errorf("memory_swap must be -1 (unlimited) or a size in bytes")
+		}
+		if swap > 0 && memory == 0 {
+			return fmt.Errorf("memory_swap requires a memory limit")
+		}
+	}
+	if swap > 0 && memory > swap {
+		return fmt.Errorf("memory_swap (%d) must be at least memory (%d)", swap, memory)
+	}
+
+	if u.CPUShares != nil && *u.CPUShares != 0 && *u.CPUShares < minCPUShares {
+		return fmt.Errorf("cpu_shares must be at least %d", minCPUShares)
+	}
+
+	if (u.CPUQuota != nil || u.CPUPeriod != nil) && current.NanoCPUs > 0 {
+		return fmt.Errorf("container limits CPUs with --cpus; cpu_quota and cpu_period cannot be combined with it")
+	}
+
+	period := current.CPUPeriod
+	if u.CPUPeriod != nil {
+		period = *u.CPUPeriod
+		if period < minCPUPeriod || period > maxCPUPeriod {
+			return fmt.Errorf("cpu_period must be between %d and %d microseconds", minCPUPeriod, maxCPUPeriod)
+		}
+	}
+	if period == 0 {
+		period = 100000 // CFS default
+	}
+
+	if u.CPUQuota != nil && *u.CPUQuota != -1 {
+		quota := *u.CPUQuota
+		if quota < minCPUQuota {
+			return fmt.Errorf("cpu_quota must be -1 (unlimited) or at least %d microseconds", minCPUQuota)
+		}
+		if hostCPUs > 0 && float64(quota)/float64(period) > float64(hostCPUs) {
+			return fmt.Errorf("cpu_quota/cpu_period allows %.2f CPUs but the host has %d", float64(quota)/float64(period), hostCPUs)
+		}
+	}
+
+	if u.CpusetCpus != nil {
+		if err := validateCpuset(*u.CpusetCpus, hostCPUs); err != nil {
+			return err
+		}
+	}
+
+	if u.PidsLimit != nil && *u.PidsLimit < -1 {
+		return fmt.Errorf("pids_limit must be -1 (unlimited) or positive")
+	}
+
+	if policy := u.RestartPolicy; policy != nil {
+		if !restartPolicies[policy.Name] {
+			return fmt.Errorf("restart_policy must be one of no, always, unless-stopped or on-failure")
+		}
+		if policy.MaximumRetryCount < 0 || (policy.MaximumRetryCount > 0 && policy.Name != "on-failure") {
+			return fmt.Errorf("maximum_retry_count is only valid with the on-failure policy")
+		}
+	}
+
+	return nil
+}
+
+// validateCpuset checks a CPU list such as "0-3,6" against the host's CPUs
+func validateCpuset(cpuset string, hostCPUs int) error {
+	if cpuset == "" {
+		return fmt.Errorf("cpuset_cpus must not be empty")
+	}
+
+	for _, part := range strings.Split(cpuset, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		if !isRange {
+			last = first
+		}
+
+		start, err := strconv.Atoi(first)
+		if err != nil || start < 0 {
+			return fmt.Errorf("invalid cpuset_cpus %q", cpuset)
+		}
+		end, err := strconv.Atoi(last)
+		if err != nil || end < start {
+			return fmt.Errorf("invalid cpuset_cpus %q", cpuset)
+		}
+		if hostCPUs > 0 && end >= hostCPUs {
+			return fmt.Errorf("cpuset_cpus %q refers to CPU %d but the host has %d", cpuset, end, hostCPUs)
+		}
+	}
+
+	return nil
+}
+
+// UpdateConfig converts the update into the Docker container update request
+func (u ResourceUpdate) UpdateConfig() dockercontainer.UpdateConfig {
+	var config dockercontainer.UpdateConfig
+
+	if u.Memory != nil {
+		config.Memory = *u.Memory
+	}
+	if u.MemorySwap != nil {
+		config.MemorySwap = *u.MemorySwap
+	}
+	if u.CPUShares != nil {
+		config.CPUShares = *u.CPUShares
+	}
+	if u.CPUQuota != nil {
+		config.CPUQuota = *u.CPUQuota
+	}
+	if u.CPUPeriod != nil {
+		config.CPUPeriod = *u.CPUPeriod
+	}
+	if u.CpusetCpus != nil {
+		config.CpusetCpus = *u.CpusetCpus
+	}
+	if u.PidsLimit != nil {
+		limit := *u.PidsLimit
+		config.PidsLimit = &limit
+	}
+	if u.RestartPolicy != nil {
+		config.RestartPolicy = dockercontainer.RestartPolicy{
+			Name:              u.RestartPolicy.Name,
+			MaximumRetryCount: u.RestartPolicy.MaximumRetryCount,
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,99 @@
+package container
+
+import "testing"
+
+func TestValidateCpuset(t *testing.T) {
+	tests := []struct {
+		cpuset   string
+		hostCPUs int
+		wantErr  bool
+	}{
+		{"0", 4, false},
+		{"0-3", 4, false},
+		{"0-1,3", 4, false},
+		{"0-3,5", 8, false},
+		{"0-3,5", 0, false}, // host size unknown
+		{"2-2", 4, false},
+		{"", 4, true},
+		{",", 4, true},
+		{"0,", 4, true},
+		{"3-1", 4, true},
+		{"-1", 4, true},
+		{"a", 4, true},
+		{"0-", 4, true},
+		{"4", 4, true},
+		{"0-3,5", 4, true},
+	}
+
+	for _, tt := range tests {
+		err := validateCpuset(tt.cpuset, tt.hostCPUs)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateCpuset(%q, %d) error = %v, wantErr %v", tt.cpuset, tt.hostCPUs, err, tt.wantErr)
+		}
+	}
+}
+
+func TestResourceUpdate_Validate(t *testing.T) {
+	int64p := func(v int64) *int64 { return &v }
+	stringp := func(v string) *string { return &v }
+
+	const hostCPUs, hostMemory = 4, 8 * 1024 * mb
+
+	tests := []struct {
+		name    string
+		update  ResourceUpdate
+		current ResourceLimits
+		wantErr bool
+	}{
+		{name: "empty", update: ResourceUpdate{}},
+		{name: "memory", update: ResourceUpdate{Memory: int64p(512 * mb)}},
+		{name: "memory below docker minimum", update: ResourceUpdate{Memory: int64p(4 * mb)}, wantErr: true},
+		{name: "memory above host", update: ResourceUpdate{Memory: int64p(16 * 1024 * mb)}, wantErr: true},
+		{name: "unlimited swap", update: ResourceUpdate{Memory: int64p(512 * mb), MemorySwap: int64p(-1)}},
+		{name: "invalid swap", update: ResourceUpdate{MemorySwap: int64p(-2)}, wantErr: true},
+		{name: "swap without memory", update: ResourceUpdate{MemorySwap: int64p(512 * mb)}, wantErr: true},
+		{name: "swap below memory", update: ResourceUpdate{Memory: int64p(512 * mb), MemorySwap: int64p(256 * mb)}, wantErr: true},
+		{
+			name:    "memory raised above current swap",
+			update:  ResourceUpdate{Memory: int64p(1024 * mb)},
+			current: ResourceLimits{Memory: 256 * mb, MemorySwap: 512 * mb},
+			wantErr: true,
+		},
+		{name: "cpu shares", update: ResourceUpdate{CPUShares: int64p(512)}},
+		{name: "cpu shares reset", update: ResourceUpdate{CPUShares: int64p(0)}},
+		{name: "cpu shares too low", update: ResourceUpdate{CPUShares: int64p(1)}, wantErr: true},
+		{name: "quota with default period", update: ResourceUpdate{CPUQuota: int64p(200000)}},
+		{name: "unlimited quota", update: ResourceUpdate{CPUQuota: int64p(-1)}},
+		{name: "quota too low", update: ResourceUpdate{CPUQuota: int64p(500)}, wantErr: true},
+		{name: "quota above host", update: ResourceUpdate{CPUQuota: int64p(500000)}, wantErr: true},
+		{
+			name:    "quota within host at shorter period",
+			update:  ResourceUpdate{CPUQuota: int64p(150000), CPUPeriod: int64p(50000)},
+			wantErr: false,
+		},
+		{name: "period out of range", update: ResourceUpdate{CPUPeriod: int64p(500)}, wantErr: true},
+		{
+			name:    "quota with --cpus",
+			update:  ResourceUpdate{CPUQuota: int64p(50000)},
+			current: ResourceLimits{NanoCPUs: 1e9},
+			wantErr: true,
+		},
+		{name: "cpuset", update: ResourceUpdate{CpusetCpus: stringp("0-1,3")}},
+		{name: "malformed cpuset range", update: ResourceUpdate{CpusetCpus: stringp("3-1")}, wantErr: true},
+		{name: "malformed cpuset list", update: ResourceUpdate{CpusetCpus: stringp(",")}, wantErr: true},
+		{name: "cpuset beyond host", update: ResourceUpdate{CpusetCpus: stringp("0-7")}, wantErr: true},
+		{name: "unlimited pids", update: ResourceUpdate{PidsLimit: int64p(-1)}},
+		{name: "invalid pids", update: ResourceUpdate{PidsLimit: int64p(-2)}, wantErr: true},
+		{name: "on-failure retries", update: ResourceUpdate{RestartPolicy: &RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}}},
+		{name: "unknown policy", update: ResourceUpdate{RestartPolicy: &RestartPolicy{Name: "sometimes"}}, wantErr: true},
+		{name: "retries without on-failure", update: ResourceUpdate{RestartPolicy: &RestartPolicy{Name: "always", MaximumRetryCount: 3}}, wantErr: true},
+		{name: "negative retries", update: ResourceUpdate{RestartPolicy: &RestartPolicy{Name: "on-failure", MaximumRetryCount: -1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := tt.update.Validate(tt.current, hostCPUs, hostMemory)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
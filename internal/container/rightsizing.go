@@ -0,0 +1,151 @@
+package container
+
+import (
+	"fmt"
+	"math"
+)
+
+// Right-sizing leaves headroom above observed peaks and only calls a limit
+// too generous when it is well above what the container needs. Lowering or
+// setting a limit also needs history covering most of the lookback, so a daily
+// peak is not missed.
+const (
+	memoryHeadroom         = 1.25 // applied to p99 memory usage
+	cpuHeadroom            = 1.5  // applied to p95 CPU usage
+	overProvisionedFactor  = 2    // limits above this multiple of the recommendation are too generous
+	throttledThreshold     = 0.1  // share of CPU periods throttled that marks a limit as too tight
+	oomGrowthFactor        = 1.5  // growth applied to a memory limit that caused OOM kills
+	minMemoryLimitMB       = 32
+	minCPULimit            = 0.05
+	minRecommendationCount = 30  // metric rollups
+	minLoweringCoverage    = 0.8 // share of the lookback the history must span to lower or set a limit
+)
+
+// UsageProfile holds the observed usage a recommendation is based on
+type UsageProfile struct {
+	Samples        int
+	Coverage       float64 // share of the lookback spanned by the samples
+	CPUP95         float64 // percent of one core
+	CPUP99         float64
+	MemP95         float64 // in MB
+	MemP99         float64
+	ThrottledRatio float64 // share of CPU periods throttled since the container started
+	OOMKills       int     // within the lookback
+}
+
+// ResourceRecommendation is a suggested limit for one resource
+type ResourceRecommendation struct {
+	Current     float64 `json:"current"`     // MB for memory, CPUs for cpu; 0 means unlimited
+	Recommended float64 `json:"recommended"` // same unit as Current
+	P95         float64 `json:"p95"`
+	P99         float64 `json:"p99"`
+	Status      string  `json:"status"` // "ok", "no_limit", "over_provisioned", "under_provisioned", "insufficient_data", "insufficient_history"
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// Actionable reports whether applying the recommendation changes the limit
+func (r ResourceRecommendation) Actionable() bool {
+	return r.Status == "no_limit" || r.Status == "over_provisioned" || r.Status == "under_provisioned"
+}
+
+// CPUs returns the CPU limit in cores, from --cpus or a CFS quota; 0 means unlimited
+func (l ResourceLimits) CPUs() float64 {
+	if l.NanoCPUs > 0 {
+		return float64(l.NanoCPUs) / 1e9
+	}
+	if l.CPUQuota > 0 {
+		period := l.CPUPeriod
+		if period == 0 {
+			period = 100000 // CFS default
+		}
+		return float64(l.CPUQuota) / float64(period)
+	}
+	return 0
+}
+
+// RecommendMemory suggests a memory limit from p99 usage. Limits that caused
+// OOM kills are raised even when observed usage looks low, since the samples
+// stop at the limit.
+func RecommendMemory(limits ResourceLimits, usage UsageProfile) ResourceRecommendation {
+	rec := ResourceRecommendation{
+		Current: float64(limits.Memory) / (1024 * 1024),
+		P95:     usage.MemP95,
+		P99:     usage.MemP99,
+	}
+	if usage.Samples < minRecommendationCount {
+		rec.Status = "insufficient_data"
+		return rec
+	}
+
+	rec.Recommended = roundUp(math.Max(usage.MemP99*memoryHeadroom, minMemoryLimitMB), 16)
+
+	switch {
+	case rec.Current > 0 && usage.OOMKills > 0:
+		rec.Recommended = math.Max(rec.Recommended, roundUp(rec.Current*oomGrowthFactor, 16))
+		rec.Status = "under_provisioned"
+		rec.Reason = "oom_killed"
+	case rec.Current == 0:
+		rec.Status = "no_limit"
+	case rec.Current < rec.Recommended:
+		rec.Status = "under_provisioned"
+		rec.Reason = "usage_near_limit"
+	case rec.Current > rec.Recommended*overProvisionedFactor:
+		rec.Status = "over_provisioned"
+	default:
+		rec.Status = "ok"
+		rec.Recommended = rec.Current
+	}
+
+	return guardLowering(rec, usage)
+}
+
+// RecommendCPU suggests a CPU limit from p95 usage. Throttled containers are
+// raised even when observed usage looks low, since the samples stop at the limit.
+func RecommendCPU(limits ResourceLimits, usage UsageProfile) ResourceRecommendation {
+	rec := ResourceRecommendation{
+		Current: limits.CPUs(),
+		P95:     usage.CPUP95,
+		P99:     usage.CPUP99,
+	}
+	if usage.Samples < minRecommendationCount {
+		rec.Status = "insufficient_data"
+		return rec
+	}
+
+	rec.Recommended = roundUp(math.Max(usage.CPUP95/100*cpuHeadroom, minCPULimit), 0.05)
+
+	switch {
+	case rec.Current > 0 && usage.ThrottledRatio >= throttledThreshold:
+		rec.Recommended = math.Max(rec.Recommended, roundUp(rec.Current*cpuHeadroom, 0.05))
+		rec.Status = "under_provisioned"
+		rec.Reason = "throttled"
+	case rec.Current == 0:
+		rec.Status = "no_limit"
+	case rec.Current < rec.Recommended:
+		rec.Status = "under_provisioned"
+		rec.Reason = "usage_near_limit"
+	case rec.Current > rec.Recommended*overProvisionedFactor:
+		rec.Status = "over_provisioned"
+	default:
+		rec.Status = "ok"
+		rec.Recommended = rec.Current
+	}
+
+	return guardLowering(rec, usage)
+}
+
+// guardLowering withholds recommendations that set or lower a limit when the
+// history does not cover enough of the lookback. Raising a limit is always safe.
+func guardLowering(rec ResourceRecommendation, usage UsageProfile) ResourceRecommendation {
+	if (rec.Status == "over_provisioned" || rec.Status == "no_limit") && usage.Coverage < minLoweringCoverage {
+		rec.Status = "insufficient_history"
+		rec.Reason = fmt.Sprintf("history covers %.0f%% of the lookback, %.0f%% needed", usage.Coverage*100, minLoweringCoverage*100)
+		rec.Recommended = rec.Current
+	}
+	return rec
+}
+
+// roundUp rounds a value up to a multiple of step
+func roundUp(value, step float64) float64 {
+	return math.Round(math.Ceil(value/step-1e-9)*step*100) / 100
+}
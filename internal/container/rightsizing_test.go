@@ -0,0 +1,104 @@
+package container
+
+import "testing"
+
+const mb = 1024 * 1024
+
+func TestResourceLimits_CPUs(t *testing.T) {
+	tests := []struct {
+		limits ResourceLimits
+		want   float64
+	}{
+		{ResourceLimits{}, 0},
+		{ResourceLimits{NanoCPUs: 1500000000}, 1.5},
+		{ResourceLimits{CPUQuota: 50000}, 0.5}, // default period
+		{ResourceLimits{CPUQuota: 50000, CPUPeriod: 25000}, 2},
+		{ResourceLimits{NanoCPUs: 1000000000, CPUQuota: 50000}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.limits.CPUs(); got != tt.want {
+			t.Errorf("CPUs(%+v) = %v, want %v", tt.limits, got, tt.want)
+		}
+	}
+}
+
+func TestRecommendMemory(t *testing.T) {
+	tests := []struct {
+		name            string
+		limitMB         int64
+		usage           UsageProfile
+		wantStatus      string
+		wantReason      string
+		wantRecommended float64
+	}{
+		{"no history", 0, UsageProfile{}, "insufficient_data", "", 0},
+		{"too few rollups", 512, UsageProfile{Samples: 29, Coverage: 1, MemP99: 100}, "insufficient_data", "", 0},
+		{"no limit", 0, UsageProfile{Samples: 30, Coverage: 1, MemP99: 100}, "no_limit", "", 128},
+		{"no limit with short history", 0, UsageProfile{Samples: 30, Coverage: 0.5, MemP99: 100}, "insufficient_history", "history covers 50% of the lookback, 80% needed", 0},
+		{"minimum limit", 0, UsageProfile{Samples: 30, Coverage: 1, MemP99: 1}, "no_limit", "", 32},
+		{"over provisioned", 1024, UsageProfile{Samples: 30, Coverage: 1, MemP99: 100}, "over_provisioned", "", 128},
+		{"over provisioned with short history", 1024, UsageProfile{Samples: 30, Coverage: 0.79, MemP99: 100}, "insufficient_history", "history covers 79% of the lookback, 80% needed", 1024},
+		{"near the limit", 100, UsageProfile{Samples: 30, Coverage: 1, MemP99: 100}, "under_provisioned", "usage_near_limit", 128},
+		// Raising a limit does not need full history
+		{"near the limit with short history", 100, UsageProfile{Samples: 30, Coverage: 0.1, MemP99: 100}, "under_provisioned", "usage_near_limit", 128},
+		{"oom killed", 256, UsageProfile{Samples: 30, Coverage: 1, MemP99: 50, OOMKills: 2}, "under_provisioned", "oom_killed", 384},
+		{"within range", 200, UsageProfile{Samples: 30, Coverage: 1, MemP99: 100}, "ok", "", 200},
+	}
+
+	for _, tt := range tests {
+		rec := RecommendMemory(ResourceLimits{Memory: tt.limitMB * mb}, tt.usage)
+		if rec.Status != tt.wantStatus || rec.Reason != tt.wantReason || rec.Recommended != tt.wantRecommended {
+			t.Errorf("%s: got %s (%q) %v, want %s (%q) %v", tt.name, rec.Status, rec.Reason, rec.Recommended, tt.wantStatus, tt.wantReason, tt.wantRecommended)
+		}
+		if rec.Actionable() != (tt.wantStatus == "no_limit" || tt.wantStatus == "over_provisioned" || tt.wantStatus == "under_provisioned") {
+			t.Errorf("%s: actionable %v", tt.name, rec.Actionable())
+		}
+	}
+}
+
+func TestRecommendCPU(t *testing.T) {
+	tests := []struct {
+		name            string
+		limits          ResourceLimits
+		usage           UsageProfile
+		wantStatus      string
+		wantReason      string
+		wantRecommended float64
+	}{
+		{"no history", ResourceLimits{}, UsageProfile{}, "insufficient_data", "", 0},
+		{"no limit", ResourceLimits{}, UsageProfile{Samples: 30, Coverage: 1, CPUP95: 50}, "no_limit", "", 0.75},
+		{"idle", ResourceLimits{}, UsageProfile{Samples: 30, Coverage: 1}, "no_limit", "", 0.05},
+		{"throttled", ResourceLimits{NanoCPUs: 500000000}, UsageProfile{Samples: 30, Coverage: 1, CPUP95: 10, ThrottledRatio: 0.2}, "under_provisioned", "throttled", 0.75},
+		{"quota within range", ResourceLimits{CPUQuota: 50000}, UsageProfile{Samples: 30, Coverage: 1, CPUP95: 30}, "ok", "", 0.5},
+		{"over provisioned", ResourceLimits{NanoCPUs: 4000000000}, UsageProfile{Samples: 30, Coverage: 1, CPUP95: 20}, "over_provisioned", "", 0.3},
+		{"over provisioned without history", ResourceLimits{NanoCPUs: 4000000000}, UsageProfile{Samples: 30, CPUP95: 20}, "insufficient_history", "history covers 0% of the lookback, 80% needed", 4},
+		{"near the limit", ResourceLimits{NanoCPUs: 1000000000}, UsageProfile{Samples: 30, Coverage: 1, CPUP95: 90}, "under_provisioned", "usage_near_limit", 1.35},
+	}
+
+	for _, tt := range tests {
+		rec := RecommendCPU(tt.limits, tt.usage)
+		if rec.Status != tt.wantStatus || rec.Reason != tt.wantReason || rec.Recommended != tt.wantRecommended {
+			t.Errorf("%s: got %s (%q) %v, want %s (%q) %v", tt.name, rec.Status, rec.Reason, rec.Recommended, tt.wantStatus, tt.wantReason, tt.wantRecommended)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		value, step, want float64
+	}{
+		{0, 16, 0},
+		{1, 16, 16},
+		{16, 16, 16},
+		{16.01, 16, 32},
+		{0.3, 0.05, 0.3}, // not pushed to 0.35 by float error
+		{0.31, 0.05, 0.35},
+	}
+
+	for _, tt := range tests {
+		if got := roundUp(tt.value, tt.step); got != tt.want {
+			t.Errorf("roundUp(%v, %v) = %v, want %v", tt.value, tt.step, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selector is a Kubernetes-style label selector such as "env=prod,team in (a,b),!canary"
+type Selector []Requirement
+
+// Requirement is a single clause of a label selector
+type Requirement struct {
+	Key      string
+	Operator string // "=", "!=", "in", "notin", "exists", "!"
+	Values   []string
+}
+
+// ParseSelector parses a comma-separated label selector. An empty string selects everything.
+// Supported clauses: key=value, key==value, key!=value, key in (a,b), key notin (a,b), key, !key.
+func ParseSelector(selector string) (Selector, error) {
+	var result Selector
+
+	for _, clause := range splitClauses(selector) {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		requirement, err := parseRequirement(clause)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, requirement)
+	}
+
+	return result, nil
+}
+
+// Matches reports whether a set of labels satisfies every requirement
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether a set of labels satisfies the requirement
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+
+	switch r.Operator {
+	case "exists":
+		return exists
+	case "!":
+		return !exists
+	case "=":
+		return exists && value == r.Values[0]
+	case "!=":
+		return !exists || value != r.Values[0]
+	case "in":
+		return exists && contains(r.Values, value)
+	case "notin":
+		return !exists || !contains(r.Values, value)
+	}
+	return false
+}
+
+// parseRequirement parses a single selector clause
+func parseRequirement(clause string) (Requirement, error) {
+	if strings.HasPrefix(clause, "!") {
+		key := strings.TrimSpace(clause[1:])
+		if !validLabelKey(key) {
+			return Requirement{}, fmt.Errorf("invalid label key in %q", clause)
+		}
+		return Requirement{Key: key, Operator: "!"}, nil
+	}
+
+	fields := strings.Fields(clause)
+	if len(fields) >= 2 && (fields[1] == "in" || fields[1] == "notin") {
+		key := fields[0]
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(clause[len(key):]), fields[1]))
+		if !validLabelKey(key) || !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+			return Requirement{}, fmt.Errorf("invalid set expression %q", clause)
+		}
+
+		var values []string
+		for _, value := range strings.Split(rest[1:len(rest)-1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return Requirement{}, fmt.Errorf("empty value set in %q", clause)
+		}
+		return Requirement{Key: key, Operator: fields[1], Values: values}, nil
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if key, value, found := strings.Cut(clause, operator); found {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if !validLabelKey(key) {
+				return Requirement{}, fmt.Errorf("invalid label key in %q", clause)
+			}
+			if operator == "==" {
+				operator = "="
+			}
+			return Requirement{Key: key, Operator: operator, Values: []string{value}}, nil
+		}
+	}
+
+	if len(fields) == 1 {
+		if !validLabelKey(fields[0]) {
+			return Requirement{}, fmt.Errorf("invalid label key in %q", clause)
+		}
+		return Requirement{Key: fields[0], Operator: "exists"}, nil
+	}
+
+	return Requirement{}, fmt.Errorf("invalid selector clause %q", clause)
+}
+
+// splitClauses splits on commas that are not inside a parenthesised value set
+func splitClauses(selector string) []string {
+	var clauses []string
+	depth, start := 0, 0
+
+	for i, ch := range selector {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				clauses = append(clauses, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(clauses, selector[start:])
+}
+
+// validLabelKey rejects empty keys and keys containing selector syntax
+func validLabelKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " !=(),")
+}
+
+// contains reports whether a slice holds a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
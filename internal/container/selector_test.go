@@ -0,0 +1,88 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     Selector
+		wantErr  bool
+	}{
+		{selector: "", want: nil},
+		{selector: " , ", want: nil},
+		{selector: "env=prod", want: Selector{{Key: "env", Operator: "=", Values: []string{"prod"}}}},
+		{selector: "env==prod", want: Selector{{Key: "env", Operator: "=", Values: []string{"prod"}}}},
+		{selector: "env != prod", want: Selector{{Key: "env", Operator: "!=", Values: []string{"prod"}}}},
+		{selector: "team in (a, b)", want: Selector{{Key: "team", Operator: "in", Values: []string{"a", "b"}}}},
+		{selector: "team notin (a)", want: Selector{{Key: "team", Operator: "notin", Values: []string{"a"}}}},
+		{selector: "canary", want: Selector{{Key: "canary", Operator: "exists"}}},
+		{selector: "!canary", want: Selector{{Key: "canary", Operator: "!"}}},
+		{
+			selector: "env=prod,team in (a,b),!canary",
+			want: Selector{
+				{Key: "env", Operator: "=", Values: []string{"prod"}},
+				{Key: "team", Operator: "in", Values: []string{"a", "b"}},
+				{Key: "canary", Operator: "!"},
+			},
+		},
+		{selector: "!", wantErr: true},
+		{selector: "=prod", wantErr: true},
+		{selector: "a b=c", wantErr: true},
+		{selector: "team in ()", wantErr: true},
+		{selector: "team in a,b", wantErr: true},
+		{selector: "team in (a", wantErr: true},
+		{selector: "foo bar", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.selector)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSelector(%q) error = %v, wantErr %v", tt.selector, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSelector(%q) = %+v, want %+v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "a"}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=dev", false},
+		{"env!=dev", true},
+		{"env!=prod", false},
+		{"missing!=x", true},
+		{"team in (a,b)", true},
+		{"team in (b,c)", false},
+		{"missing in (a)", false},
+		{"team notin (b)", true},
+		{"team notin (a)", false},
+		{"missing notin (a)", true},
+		{"env", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!env", false},
+		{"env=prod,team in (a,b),!canary", true},
+		{"env=prod,canary", false},
+	}
+
+	for _, tt := range tests {
+		selector, err := ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector(%q): %v", tt.selector, err)
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("%q.Matches(%v) = %v, want %v", tt.selector, labels, got, tt.want)
+		}
+	}
+}
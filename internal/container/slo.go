@@ -0,0 +1,45 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SLOReport holds the error budget of an availability objective over a window
+type SLOReport struct {
+	TargetPercent    float64 `json:"target_percent"`
+	Window           string  `json:"window"`
+	BudgetSeconds    float64 `json:"budget_seconds"` // allowed downtime over the observed part of the window
+	ConsumedSeconds  float64 `json:"consumed_seconds"`
+	RemainingSeconds float64 `json:"remaining_seconds"` // negative once the budget is exhausted
+	RemainingPercent float64 `json:"remaining_percent"`
+	Breached         bool    `json:"breached"`
+}
+
+// ParseSLOTarget parses an availability objective such as "99.9" or "99.9%"
+func ParseSLOTarget(value string) (float64, error) {
+	target, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil || target <= 0 || target >= 100 {
+		return 0, fmt.Errorf("invalid SLO target %q (use a percentage between 0 and 100, e.g. 99.9)", value)
+	}
+	return target, nil
+}
+
+// CalculateErrorBudget compares downtime with the downtime an objective allows
+func CalculateErrorBudget(target float64, window string, observedSeconds, downtimeSeconds float64) SLOReport {
+	report := SLOReport{
+		TargetPercent:   target,
+		Window:          window,
+		BudgetSeconds:   observedSeconds * (100 - target) / 100,
+		ConsumedSeconds: downtimeSeconds,
+	}
+
+	report.RemainingSeconds = report.BudgetSeconds - report.ConsumedSeconds
+	if report.BudgetSeconds > 0 {
+		report.RemainingPercent = report.RemainingSeconds / report.BudgetSeconds * 100.0
+	}
+	report.Breached = report.RemainingSeconds < 0
+
+	return report
+}
@@ -6,6 +6,9 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container" // ⬅️ NEW IMPORT
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 )
 
@@ -39,6 +42,16 @@ func (c *Client) ContainerStats(ctx context.Context, containerID string) (io.Rea
     return statsJSON.Body, nil 
 }
 
+// ContainerStatsStream returns a stream of stats decoded one JSON object at a time.
+// The daemon sends a new sample about every second until the container stops.
+func (c *Client) ContainerStatsStream(ctx context.Context, containerID string) (io.ReadCloser, error) {
+	statsJSON, err := c.cli.ContainerStats(ctx, containerID, true)
+	if err != nil {
+		return nil, err
+	}
+	return statsJSON.Body, nil
+}
+
 // ContainerLogs returns a reader for container logs
 func (c *Client) ContainerLogs(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
 	return c.cli.ContainerLogs(ctx, containerID, options)
@@ -52,4 +65,83 @@ func (c *Client) ContainerTop(ctx context.Context, containerID string, arguments
 // ContainerInspect returns the detailed information of a container
 func (c *Client) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	return c.cli.ContainerInspect(ctx, containerID)
-}
\ No newline at end of file
+}
+
+// Events returns the daemon event stream along with a channel reporting stream errors
+func (c *Client) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	return c.cli.Events(ctx, options)
+}
+
+// ContainerStart starts a stopped container
+func (c *Client) ContainerStart(ctx context.Context, containerID string) error {
+	return c.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+}
+
+// ContainerStop stops a running container using its configured stop timeout
+func (c *Client) ContainerStop(ctx context.Context, containerID string) error {
+	return c.cli.ContainerStop(ctx, containerID, container.StopOptions{})
+}
+
+// ContainerUpdate changes the resource limits and restart policy of a container without recreating it
+func (c *Client) ContainerUpdate(ctx context.Context, containerID string, config container.UpdateConfig) error {
+	_, err := c.cli.ContainerUpdate(ctx, containerID, config)
+	return err
+}
+
+// Info returns system-wide information about the Docker daemon
+func (c *Client) Info(ctx context.Context) (types.Info, error) {
+	return c.cli.Info(ctx)
+}
+
+// ImageList lists local images
+func (c *Client) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return c.cli.ImageList(ctx, options)
+}
+
+// ImageHistory returns the layer history of an image
+func (c *Client) ImageHistory(ctx context.Context, imageID string) ([]image.HistoryResponseItem, error) {
+	return c.cli.ImageHistory(ctx, imageID)
+}
+
+// VolumeList lists volumes
+func (c *Client) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	return c.cli.VolumeList(ctx, options)
+}
+
+// NetworkList lists networks
+func (c *Client) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	return c.cli.NetworkList(ctx, options)
+}
+
+// DiskUsage returns the disk space used by images, containers, volumes and the build cache
+func (c *Client) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	return c.cli.DiskUsage(ctx, options)
+}
+
+// ContainerRemove removes a stopped container
+func (c *Client) ContainerRemove(ctx context.Context, containerID string) error {
+	return c.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{})
+}
+
+// ImageRemove removes an image by ID or tag, and its untagged parents. Without force
+// the daemon refuses images used by any container and images with several tags;
+// removing a tag only untags the image until its last tag goes.
+func (c *Client) ImageRemove(ctx context.Context, imageID string) error {
+	_, err := c.cli.ImageRemove(ctx, imageID, types.ImageRemoveOptions{PruneChildren: true})
+	return err
+}
+
+// VolumeRemove removes a volume that is not in use
+func (c *Client) VolumeRemove(ctx context.Context, name string) error {
+	return c.cli.VolumeRemove(ctx, name, false)
+}
+
+// NetworkRemove removes a network
+func (c *Client) NetworkRemove(ctx context.Context, networkID string) error {
+	return c.cli.NetworkRemove(ctx, networkID)
+}
+
+// BuildCachePrune removes build cache records matching the options
+func (c *Client) BuildCachePrune(ctx context.Context, options types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error) {
+	return c.cli.BuildCachePrune(ctx, options)
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container" // This is needed for ContainerTop return type
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
 )
 
 // DockerService defines the set of Docker client methods required by the application handlers.
@@ -18,6 +21,9 @@ type DockerService interface {
     // ContainerStats is used in HandleStats
 	ContainerStats(ctx context.Context, containerID string) (io.ReadCloser, error)
 
+	// ContainerStatsStream is used by the stats manager to keep one subscription per running container
+	ContainerStatsStream(ctx context.Context, containerID string) (io.ReadCloser, error)
+
     // ContainerLogs is used in HandleLogs
 	ContainerLogs(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error)
 
@@ -26,4 +32,33 @@ type DockerService interface {
 
 	// ContainerInspect is used in HandleStats to get detailed info like RestartCount
 	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
-}
\ No newline at end of file
+
+	// Events is used by the monitor to follow container lifecycle changes
+	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+
+	// ContainerStart and ContainerStop are used by project-wide actions
+	ContainerStart(ctx context.Context, containerID string) error
+	ContainerStop(ctx context.Context, containerID string) error
+
+	// ContainerUpdate is used to apply resource limits and restart policies to a running container
+	ContainerUpdate(ctx context.Context, containerID string, config container.UpdateConfig) error
+
+	// Info is used to identify the host the containers run on
+	Info(ctx context.Context) (types.Info, error)
+
+	// ImageList and ImageHistory are used by the image inventory
+	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
+	ImageHistory(ctx context.Context, imageID string) ([]image.HistoryResponseItem, error)
+
+	// VolumeList, NetworkList and DiskUsage are used by the volume, network and disk usage inventory
+	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+	DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
+
+	// ContainerRemove, ImageRemove, VolumeRemove, NetworkRemove and BuildCachePrune are used by prune operations
+	ContainerRemove(ctx context.Context, containerID string) error
+	ImageRemove(ctx context.Context, imageID string) error
+	VolumeRemove(ctx context.Context, name string) error
+	NetworkRemove(ctx context.Context, networkID string) error
+	BuildCachePrune(ctx context.Context, options types.BuildCachePruneOptions) (*types.BuildCachePruneReport, error)
+}
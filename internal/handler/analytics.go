@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// HandleExitAnalytics handles the /api/analytics/exits endpoint
+func (h *Handler) HandleExitAnalytics(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Report the last 24 hours by default
+	since, until, err := parseTimeRange(r, 24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := h.HistoryStore.GetExitAnalytics(since, until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// availabilityWindows are the windows reported by /api/analytics/availability
+var availabilityWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"day", 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+}
+
+// RestartReport holds the restart statistics and accumulated uptime of a container
+type RestartReport struct {
+	storage.ContainerRestartStats
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// AvailabilityReport holds a container's availability per window and its error budget
+type AvailabilityReport struct {
+	ContainerID   string                                   `json:"container_id"`
+	ContainerName string                                   `json:"container_name"`
+	Windows       map[string]storage.ContainerAvailability `json:"windows"`
+	SLO           *container.SLOReport                     `json:"slo,omitempty"`
+	SLOError      string                                   `json:"slo_error,omitempty"`
+}
+
+// HandleRestartAnalytics handles the /api/analytics/restarts endpoint
+func (h *Handler) HandleRestartAnalytics(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit := 10
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		l, err := strconv.Atoi(limitParam)
+		if err != nil || l <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	stats, err := h.HistoryStore.GetMostRestartedContainers(limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reports := make([]RestartReport, 0, len(stats))
+	for _, s := range stats {
+		uptime, err := h.HistoryStore.GetContainerUptime(s.ContainerID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reports = append(reports, RestartReport{ContainerRestartStats: s, UptimeSeconds: uptime.Seconds()})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// HandleAvailability handles the /api/analytics/availability endpoint.
+// Containers labelled with an SLO target (e.g. gocontainerops.slo=99.9) also get
+// an error-budget report over the month window.
+func (h *Handler) HandleAvailability(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	now := time.Now()
+	byID := make(map[string]*AvailabilityReport)
+	var order []string
+
+	for _, window := range availabilityWindows {
+		results, err := h.HistoryStore.GetAvailability(now.Add(-window.Duration), now)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, availability := range results {
+			report, exists := byID[availability.ContainerID]
+			if !exists {
+				report = &AvailabilityReport{
+					ContainerID:   availability.ContainerID,
+					ContainerName: availability.ContainerName,
+					Windows:       make(map[string]storage.ContainerAvailability),
+				}
+				byID[availability.ContainerID] = report
+				order = append(order, availability.ContainerID)
+			}
+			report.Windows[window.Name] = availability
+		}
+	}
+
+	// SLO targets come from container labels
+	labels := make(map[string]map[string]string)
+	if containers, err := h.DockerService.ListContainers(context.Background(), types.ContainerListOptions{All: true}); err == nil {
+		for _, c := range containers {
+			labels[c.ID[:12]] = c.Labels
+		}
+	} else {
+		log.Printf("Error listing containers: %v", err)
+	}
+
+	reports := make([]AvailabilityReport, 0, len(order))
+	for _, id := range order {
+		report := byID[id]
+		if value, exists := labels[id][h.Config.SLOLabel]; exists && h.Config.SLOLabel != "" {
+			target, err := container.ParseSLOTarget(value)
+			if err != nil {
+				report.SLOError = err.Error()
+			} else if month, ok := report.Windows["month"]; ok {
+				slo := container.CalculateErrorBudget(target, "month", month.ObservedSeconds, month.DowntimeSeconds)
+				report.SLO = &slo
+			}
+		}
+		reports = append(reports, *report)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
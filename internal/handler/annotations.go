@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gocontainerops/internal/storage"
+)
+
+// ChartAnnotation marks a point in time on a container's metric charts
+type ChartAnnotation struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Type      string            `json:"type"`
+	Label     string            `json:"label"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// annotatedEventTypes are the history events worth drawing on metric charts
+var annotatedEventTypes = map[string]bool{
+	"config_change": true,
+	"restart":       true,
+	"stop":          true,
+	"crash_loop":    true,
+	"anomaly":       true,
+}
+
+// HandleAnnotations handles the /api/annotations/:id endpoint.
+// It returns config changes and lifecycle events in chronological order so the
+// UI can overlay them on the series returned by /api/history/:id.
+func (h *Handler) HandleAnnotations(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/annotations/")
+
+	// Match the default window of /api/history/:id
+	since, until, err := parseTimeRange(r, time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.HistoryStore.GetEvents(id, 1000)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	annotations := []ChartAnnotation{}
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if !annotatedEventTypes[event.EventType] || event.Timestamp.Before(since) || event.Timestamp.After(until) {
+			continue
+		}
+		annotations = append(annotations, ChartAnnotation{
+			Timestamp: event.Timestamp,
+			Type:      event.EventType,
+			Label:     annotationLabel(event),
+			Details:   event.Details,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(annotations)
+}
+
+// annotationLabel renders a short chart label for an event
+func annotationLabel(event storage.ContainerEvent) string {
+	switch event.EventType {
+	case "config_change":
+		return fmt.Sprintf("Config v%s: %s", event.Details["version"], event.Details["changes"])
+	case "stop":
+		if reason := event.Details["reason"]; reason != "" {
+			return fmt.Sprintf("Stopped (%s)", reason)
+		}
+		return "Stopped"
+	case "restart":
+		return "Restarted"
+	case "crash_loop":
+		return "Crash loop detected"
+	case "anomaly":
+		return fmt.Sprintf("Anomaly: %s", event.Details["metric"])
+	}
+	return event.EventType
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// AnomalyReport holds the anomalies in progress and the anomaly events of a time range
+type AnomalyReport struct {
+	Active  []container.Anomaly      `json:"active"`
+	History []storage.ContainerEvent `json:"history"`
+}
+
+// HandleAnomalies handles the /api/anomalies endpoint. It returns the anomalies in
+// progress and the anomaly events of the last 24 hours (or since/until), optionally
+// for a single container (?container=).
+func (h *Handler) HandleAnomalies(w http.ResponseWriter, r *http.Request) {
+	if h.Anomalies == nil {
+		http.Error(w, "Anomaly detection not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	since, until, err := parseTimeRange(r, 24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	containerID := r.URL.Query().Get("container")
+
+	report := AnomalyReport{
+		Active:  []container.Anomaly{},
+		History: []storage.ContainerEvent{},
+	}
+	for _, anomaly := range h.Anomalies.Active() {
+		if containerID == "" || anomaly.ID == containerID || anomaly.Name == containerID {
+			report.Active = append(report.Active, anomaly)
+		}
+	}
+
+	if h.HistoryStore != nil {
+		events, err := h.HistoryStore.QueryEvents(storage.EventQuery{
+			Container: containerID,
+			Types:     []string{"anomaly"},
+			Since:     since,
+			Until:     until,
+			Limit:     maxEventLimit,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		report.History = append(report.History, events...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Caller roles
+const (
+	RoleViewer = "viewer"
+	RoleAdmin  = "admin"
+)
+
+// callerRole returns the role of the request's caller. Callers presenting the
+// configured admin token as a bearer token are admins; everyone else is a viewer.
+func (h *Handler) callerRole(r *http.Request) string {
+	if h.Config.AdminToken == "" {
+		return RoleViewer
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return RoleViewer
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.Config.AdminToken)) == 1 {
+		return RoleAdmin
+	}
+	return RoleViewer
+}
+
+// isElevated reports whether the caller may see unredacted data and perform changes
+func (h *Handler) isElevated(r *http.Request) bool {
+	return h.callerRole(r) == RoleAdmin
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// handleConfigHistory returns the versioned configuration history of a container.
+// The name may be a container name or ID, or a compose "project/service" key.
+func (h *Handler) handleConfigHistory(w http.ResponseWriter, r *http.Request, name string) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	key := name
+	if info, err := h.DockerService.ContainerInspect(context.Background(), name); err == nil {
+		view := container.BuildInspectView(info)
+		key = container.ConfigKey(view.Labels, view.Name)
+	}
+
+	history, err := h.HistoryStore.GetConfigHistory(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !h.isElevated(r) {
+		for i := range history {
+			history[i] = h.redactConfigSnapshot(history[i])
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// redactConfigSnapshot masks secret-looking environment values in a snapshot and its changes
+func (h *Handler) redactConfigSnapshot(snapshot storage.ConfigSnapshot) storage.ConfigSnapshot {
+	snapshot.Env, _ = container.RedactEnv(snapshot.Env, h.Config.SecretPatterns)
+
+	changes := make([]storage.ConfigChange, len(snapshot.Changes))
+	for i, change := range snapshot.Changes {
+		name, isEnv := strings.CutPrefix(change.Field, "env.")
+		if isEnv && container.IsSecretName(name, h.Config.SecretPatterns) {
+			if change.Old != "" {
+				change.Old = container.RedactedValue
+			}
+			if change.New != "" {
+				change.New = container.RedactedValue
+			}
+		}
+		changes[i] = change
+	}
+	snapshot.Changes = changes
+
+	return snapshot
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/security"
+)
+
+// HandleContainer handles the /api/containers/:id, /api/containers/:id/resources
+// and /api/containers/:name/config-history endpoints
+func (h *Handler) HandleContainer(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/containers/"), "/")
+
+	if id, found := strings.CutSuffix(path, "/resources"); found && id != "" && !strings.Contains(id, "/") {
+		if r.Method != http.MethodPatch {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleResourceUpdate(w, r, id)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Compose keys contain a slash ("project/service"), so match the suffix first
+	if name, found := strings.CutSuffix(path, "/config-history"); found && name != "" {
+		h.handleConfigHistory(w, r, name)
+		return
+	}
+
+	if path == "" || strings.Contains(path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.handleContainerInspect(w, r, path)
+}
+
+// handleContainerInspect returns the curated inspect view of a container.
+// Secret-looking environment values, log driver options (e.g. splunk-token),
+// command-line arguments and labels are redacted unless the caller is elevated.
+func (h *Handler) handleContainerInspect(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := context.Background()
+
+	info, err := h.DockerService.ContainerInspect(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	view := container.BuildInspectView(info)
+	if !h.isElevated(r) {
+		view = h.redactInspectView(view)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
+
+// redactInspectView masks values whose names match the secret patterns, then
+// runs the secret scanner over command arguments and label values to catch
+// tokens passed positionally or under innocuous names
+func (h *Handler) redactInspectView(view container.InspectView) container.InspectView {
+	patterns := h.Config.SecretPatterns
+
+	var commandRedacted, entrypointRedacted bool
+	view.Env, view.EnvRedacted = container.RedactEnv(view.Env, patterns)
+	view.LogOptions, view.LogOptionsRedacted = container.RedactOptions(view.LogOptions, patterns)
+	view.Command, commandRedacted = container.RedactArgs(view.Command, patterns)
+	view.Entrypoint, entrypointRedacted = container.RedactArgs(view.Entrypoint, patterns)
+	view.Labels, view.LabelsRedacted = container.RedactOptions(view.Labels, patterns)
+
+	if h.Secrets != nil {
+		commandRedacted = h.redactScanned(view.Command) || commandRedacted
+		entrypointRedacted = h.redactScanned(view.Entrypoint) || entrypointRedacted
+		for key, value := range view.Labels {
+			if masked := h.Secrets.RedactWith(value, security.RedactionMask); masked != value {
+				view.Labels[key] = masked
+				view.LabelsRedacted = true
+			}
+		}
+	}
+	view.CommandRedacted = commandRedacted || entrypointRedacted
+
+	return view
+}
+
+// redactScanned masks the secrets the scanner finds in each value, in place.
+// It reports whether anything was redacted.
+func (h *Handler) redactScanned(values []string) bool {
+	redacted := false
+	for i, value := range values {
+		if masked := h.Secrets.RedactWith(value, security.RedactionMask); masked != value {
+			values[i] = masked
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+// ResourceUpdateResult reports a container's limits before and after an update
+type ResourceUpdateResult struct {
+	ID                    string                   `json:"id"`
+	Name                  string                   `json:"name"`
+	Previous              container.ResourceLimits `json:"previous"`
+	Current               container.ResourceLimits `json:"current"`
+	PreviousRestartPolicy container.RestartPolicy  `json:"previous_restart_policy"`
+	RestartPolicy         container.RestartPolicy  `json:"restart_policy"`
+}
+
+// handleResourceUpdate changes a container's limits and restart policy in place.
+// The request is validated against the host's CPUs and memory. Requires the admin role.
+func (h *Handler) handleResourceUpdate(w http.ResponseWriter, r *http.Request, id string) {
+	if !h.isElevated(r) {
+		http.Error(w, "Updating resources requires the admin role", http.StatusForbidden)
+		return
+	}
+
+	var update container.ResourceUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if update.Empty() {
+		http.Error(w, "No resource changes requested", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	info, err := h.DockerService.ContainerInspect(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	previous := container.BuildInspectView(info)
+
+	hostInfo, err := h.DockerService.Info(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := update.Validate(previous.Resources, hostInfo.NCPU, hostInfo.MemTotal); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.updateResources(ctx, r, info, update.UpdateConfig(), "api"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	info, err = h.DockerService.ContainerInspect(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	current := container.BuildInspectView(info)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ResourceUpdateResult{
+		ID:                    current.ID,
+		Name:                  current.Name,
+		Previous:              previous.Resources,
+		Current:               current.Resources,
+		PreviousRestartPolicy: previous.RestartPolicy,
+		RestartPolicy:         current.RestartPolicy,
+	})
+}
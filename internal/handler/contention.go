@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gocontainerops/internal/container"
+)
+
+// ContentionReport holds the contention episodes in progress and those that ended within a time range
+type ContentionReport struct {
+	Active   []container.ContentionEpisode `json:"active"`
+	Episodes []container.ContentionEpisode `json:"episodes"`
+}
+
+// HandleContention handles the /api/contention endpoint. resource=cpu|io limits
+// the report to one resource; ended episodes default to the last 24 hours.
+func (h *Handler) HandleContention(w http.ResponseWriter, r *http.Request) {
+	if h.Contention == nil {
+		http.Error(w, "Contention detection is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	resource := r.URL.Query().Get("resource")
+	if resource != "" && resource != container.ContentionCPU && resource != container.ContentionIO {
+		http.Error(w, "resource must be cpu or io", http.StatusBadRequest)
+		return
+	}
+
+	since, until, err := parseTimeRange(r, 24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := ContentionReport{
+		Active:   []container.ContentionEpisode{},
+		Episodes: []container.ContentionEpisode{},
+	}
+	for _, episode := range h.Contention.Active(time.Now()) {
+		if resource == "" || episode.Resource == resource {
+			report.Active = append(report.Active, episode)
+		}
+	}
+	for _, episode := range h.Contention.Recent(since, until) {
+		if resource == "" || episode.Resource == resource {
+			report.Episodes = append(report.Episodes, episode)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
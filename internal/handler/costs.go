@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// costPeriods are the periods accepted by /api/costs
+var costPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// minCostCoverage is the share of the period history must cover before a report is
+// extrapolated from it; shorter history needs allow_partial=true
+const minCostCoverage = 0.1
+
+// partialCostCoverage marks reports extrapolated from less than this share of the period
+const partialCostCoverage = 0.9
+
+// CostReport holds the host cost of a period split across groups of containers
+type CostReport struct {
+	Period        string              `json:"period"`
+	Mode          string              `json:"mode"` // "usage", "reservation"
+	GroupBy       string              `json:"group_by"`
+	Currency      string              `json:"currency"`
+	Since         time.Time           `json:"since"`
+	Until         time.Time           `json:"until"`
+	PeriodHours   float64             `json:"period_hours"`
+	ObservedHours float64             `json:"observed_hours"` // history the usage mode extrapolates from
+	Coverage      float64             `json:"coverage"`       // observed hours over period hours
+	Partial       bool                `json:"partial"`        // extrapolated from under 90% of the period
+	HostCPUs      int                 `json:"host_cpus"`
+	HostMemoryGB  float64             `json:"host_memory_gb"`
+	Rates         container.CostRates `json:"rates"`
+	container.CostAllocation
+}
+
+// HandleCosts handles the /api/costs endpoint. Host cost over the period (day, week
+// or month) is attributed to containers by average CPU and memory usage (mode=usage)
+// or by their limits (mode=reservation), grouped by group_by (default label:team).
+// format=csv returns the groups and idle cost as CSV. Reports extrapolated from
+// under a tenth of the period are refused unless allow_partial=true.
+func (h *Handler) HandleCosts(w http.ResponseWriter, r *http.Request) {
+	if h.Config.CostHostHourly == 0 && h.Config.CostCPUHourly == 0 && h.Config.CostMemoryGBHourly == 0 {
+		http.Error(w, "Cost rates are not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	period := query.Get("period")
+	if period == "" {
+		period = "month"
+	}
+	duration, ok := costPeriods[period]
+	if !ok {
+		http.Error(w, "period must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
+	mode := query.Get("mode")
+	if mode == "" {
+		mode = "usage"
+	}
+	if mode != "usage" && mode != "reservation" {
+		http.Error(w, "mode must be usage or reservation", http.StatusBadRequest)
+		return
+	}
+
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = "label:team"
+	}
+	keyFunc, err := container.GroupKeyFunc(groupBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	hostInfo, err := h.DockerService.Info(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	report := CostReport{
+		Period:       period,
+		Mode:         mode,
+		GroupBy:      groupBy,
+		Currency:     h.Config.CostCurrency,
+		Since:        now.Add(-duration),
+		Until:        now,
+		PeriodHours:  duration.Hours(),
+		HostCPUs:     hostInfo.NCPU,
+		HostMemoryGB: float64(hostInfo.MemTotal) / (1024 * 1024 * 1024),
+	}
+	report.Rates = container.HostCostRates(h.Config.CostHostHourly, h.Config.CostCPUHourly, h.Config.CostMemoryGBHourly, report.HostCPUs, report.HostMemoryGB)
+
+	// Rollups span the whole period and keep the labels of removed containers
+	summaries, err := h.HistoryStore.GetFleetRollupSummary(report.Since, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	usages, observed, err := h.costUsages(ctx, summaries, mode, keyFunc, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report.ObservedHours = observed.Hours()
+	report.Coverage = math.Min(report.ObservedHours/report.PeriodHours, 1)
+	report.Partial = report.Coverage < partialCostCoverage
+	if report.Coverage < minCostCoverage && query.Get("allow_partial") != "true" {
+		http.Error(w, fmt.Sprintf("History covers %.1f of the %.0f hours in the period; pass allow_partial=true to extrapolate from it", report.ObservedHours, report.PeriodHours), http.StatusConflict)
+		return
+	}
+	report.CostAllocation = container.AllocateCosts(usages, report.Rates, report.HostCPUs, report.HostMemoryGB, report.PeriodHours)
+
+	if format == "csv" {
+		writeCostCSV(w, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// costUsages builds the usage attributed to each container. Usage comes from metric
+// rollups and is assumed to hold for the whole period; a container only present for
+// part of the observed history is charged for that part, and grouped by the labels
+// recorded with its rollups. Reservations come from the limits of running
+// containers; unlimited resources reserve nothing.
+func (h *Handler) costUsages(ctx context.Context, summaries []storage.MetricSummary, mode string, keyFunc func(container.ContainerData) string, now time.Time) ([]container.CostUsage, time.Duration, error) {
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	host := h.host(ctx)
+	groupOf := func(id, name, image string, labels map[string]string) string {
+		key := keyFunc(container.ContainerData{
+			ID:      id,
+			Name:    name,
+			Image:   image,
+			Labels:  labels,
+			Project: container.ParseCompose(labels).Project,
+			Host:    host,
+		})
+		if key == "" {
+			return container.NoGroup
+		}
+		return key
+	}
+	byID := make(map[string]types.Container)
+	for _, c := range containers {
+		byID[c.ID[:12]] = c
+	}
+
+	// The observed window runs from the oldest sample in the period
+	start := now
+	for _, summary := range summaries {
+		if summary.Samples > 0 && summary.FirstSample.Before(start) {
+			start = summary.FirstSample
+		}
+	}
+	observed := now.Sub(start)
+
+	presence := make(map[string]float64)
+	for _, summary := range summaries {
+		if summary.Samples == 0 || observed <= 0 {
+			continue
+		}
+		// A container still running at the end is present until now
+		end := summary.LastSample
+		if c, exists := byID[summary.ContainerID]; exists && c.State == "running" {
+			end = now
+		}
+		presence[summary.ContainerID] = math.Min(end.Sub(summary.FirstSample).Seconds()/observed.Seconds(), 1)
+	}
+
+	var usages []container.CostUsage
+	if mode == "usage" {
+		for _, summary := range summaries {
+			if summary.Samples == 0 {
+				continue
+			}
+			usages = append(usages, container.CostUsage{
+				Group:    groupOf(summary.ContainerID, summary.Name, summary.Image, summary.Labels),
+				CPUCores: summary.CPUPercent.Mean / 100,
+				MemoryGB: summary.MemUsage.Mean / 1024,
+				Presence: presence[summary.ContainerID],
+			})
+		}
+		return usages, observed, nil
+	}
+
+	for _, c := range containers {
+		if c.State != "running" {
+			continue
+		}
+		info, err := h.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Printf("Error inspecting container %s: %v", c.ID[:12], err)
+			continue
+		}
+		limits := container.BuildInspectView(info).Resources
+
+		memory := limits.MemoryReservation
+		if memory == 0 {
+			memory = limits.Memory
+		}
+
+		// Containers without history have only been seen running now; charge the whole period
+		share, known := presence[c.ID[:12]]
+		if !known {
+			share = 1
+		}
+
+		usages = append(usages, container.CostUsage{
+			Group:    groupOf(c.ID[:12], containerName(c), c.Image, c.Labels),
+			CPUCores: limits.CPUs(),
+			MemoryGB: float64(memory) / (1024 * 1024 * 1024),
+			Presence: share,
+		})
+	}
+
+	return usages, observed, nil
+}
+
+// writeCostCSV writes one row per group followed by the idle and total rows
+func writeCostCSV(w http.ResponseWriter, report CostReport) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"costs-%s-%s.csv\"", report.Period, report.Until.Format("2006-01-02")))
+
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	hours := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) }
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"group", "containers", "cpu_core_hours", "memory_gb_hours", "cpu_cost", "memory_cost", "cost", "share", "currency", "mode", "since", "until", "coverage"})
+
+	currency := csvCell(report.Currency)
+	since := report.Since.Format(time.RFC3339)
+	until := report.Until.Format(time.RFC3339)
+	coverage := strconv.FormatFloat(report.Coverage, 'f', 4, 64)
+	for _, line := range report.Groups {
+		writer.Write([]string{
+			csvCell(line.Group),
+			strconv.Itoa(line.Containers),
+			hours(line.CPUCoreHours),
+			hours(line.MemoryGBHours),
+			money(line.CPUCost),
+			money(line.MemoryCost),
+			money(line.Cost),
+			strconv.FormatFloat(line.Share, 'f', 4, 64),
+			currency, report.Mode, since, until, coverage,
+		})
+	}
+
+	idleShare := 0.0
+	if report.TotalCost > 0 {
+		idleShare = report.IdleCost / report.TotalCost
+	}
+	writer.Write([]string{"(idle)", "", "", "", money(report.IdleCPUCost), money(report.IdleMemoryCost), money(report.IdleCost), strconv.FormatFloat(idleShare, 'f', 4, 64), currency, report.Mode, since, until, coverage})
+	writer.Write([]string{"(total)", "", "", "", "", "", money(report.TotalCost), "1.0000", currency, report.Mode, since, until, coverage})
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error writing cost CSV: %v", err)
+	}
+}
+
+// csvCell keeps spreadsheets from evaluating a label value as a formula
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"gocontainerops/internal/container"
+)
+
+// crashLooping returns the containers the sampler currently flags as crash-looping,
+// limited to the given sample so selectors apply
+func (h *Handler) crashLooping(results []container.ContainerData) []container.CrashLoopInfo {
+	if h.CrashLoops == nil {
+		return nil
+	}
+
+	ids := make(map[string]bool, len(results))
+	for _, data := range results {
+		ids[data.ID] = true
+	}
+
+	var flagged []container.CrashLoopInfo
+	for _, info := range h.CrashLoops.Flagged() {
+		if ids[info.ID] {
+			flagged = append(flagged, info)
+		}
+	}
+	return flagged
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+const (
+	// defaultEventLimit and maxEventLimit bound a page of /api/events
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+
+	// eventStreamBuffer is how many events a stream client may fall behind before
+	// it is disconnected and expected to resume with Last-Event-ID
+	eventStreamBuffer = 256
+)
+
+// eventStreamEpoch prefixes stream event IDs. Event IDs restart at 1 with the
+// process, so an ID from another epoch says nothing about what the client missed.
+var eventStreamEpoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// HandleEventStream handles the /api/events/stream Server-Sent Events endpoint.
+// It accepts the same filters as /api/events and, after a reconnect, replays the
+// events missed since the Last-Event-ID header (or last_event_id parameter).
+// Stream IDs are "<epoch>-<event id>"; a client resuming with an ID from before a
+// restart is replayed every stored event matching its filters. When the replay
+// cannot be complete, because the process restarted or matching events were
+// dropped by retention, a "gap" event precedes it so the client can reload.
+func (h *Handler) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query, err := parseEventQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.Limit, query.Before = 0, 0
+
+	// Containers created after connecting are not matched until the client reconnects
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.IDs, err = h.selectedContainerIDs(r.Context(), selector); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var lastSent uint64
+	gapReason := ""
+	resume := lastEventID != ""
+	if resume {
+		id, sameEpoch, err := parseLastEventID(lastEventID)
+		if err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		if sameEpoch {
+			lastSent = id
+			if evicted, err := h.HistoryStore.EventsEvicted(id, query.Types); err == nil && evicted {
+				gapReason = "evicted"
+			}
+		} else {
+			gapReason = "restarted"
+		}
+	}
+
+	// Subscribe before replaying so nothing added in between is lost;
+	// the ID check below drops the overlap
+	live, unsubscribe := h.HistoryStore.SubscribeEvents(eventStreamBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	controller := http.NewResponseController(w)
+	send := func(payload string) bool {
+		controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := fmt.Fprint(w, payload); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+	sendEvent := func(event storage.ContainerEvent) bool {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return false
+		}
+		lastSent = event.ID
+		return send(fmt.Sprintf("id: %s-%d\nevent: %s\ndata: %s\n\n", eventStreamEpoch, event.ID, event.EventType, data))
+	}
+
+	if !send(": connected\n\n") {
+		return
+	}
+
+	if gapReason != "" {
+		data, _ := json.Marshal(map[string]string{"reason": gapReason, "last_event_id": lastEventID})
+		if !send(fmt.Sprintf("event: gap\ndata: %s\n\n", data)) {
+			return
+		}
+	}
+
+	if resume {
+		replay := query
+		replay.After = lastSent
+		missed, err := h.HistoryStore.QueryEvents(replay)
+		if err != nil {
+			return
+		}
+		// QueryEvents is newest first; replay in order
+		for i := len(missed) - 1; i >= 0; i-- {
+			if !sendEvent(missed[i]) {
+				return
+			}
+		}
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if !send(": keep-alive\n\n") {
+				return
+			}
+		case event, ok := <-live:
+			if !ok {
+				// Fell too far behind; the client reconnects and resumes from lastSent
+				return
+			}
+			if event.ID <= lastSent || !query.Matches(event) {
+				continue
+			}
+			if !sendEvent(event) {
+				return
+			}
+		}
+	}
+}
+
+// parseLastEventID splits a stream event ID into the event ID and whether it was
+// issued by this process. Plain numeric IDs, as listed by /api/events, are taken
+// to be current.
+func parseLastEventID(value string) (uint64, bool, error) {
+	epoch, idPart, found := strings.Cut(value, "-")
+	if !found {
+		id, err := strconv.ParseUint(value, 10, 64)
+		return id, true, err
+	}
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	return id, epoch == eventStreamEpoch, err
+}
+
+// selectedContainerIDs returns the IDs of the current containers whose labels match
+// the selector, to filter events by since they carry no labels of their own. It
+// returns nil, matching every event, for an empty selector.
+func (h *Handler) selectedContainerIDs(ctx context.Context, selector container.Selector) ([]string, error) {
+	if len(selector) == 0 {
+		return nil, nil
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, c := range filterBySelector(containers, selector) {
+		ids = append(ids, c.ID[:12])
+	}
+	return ids, nil
+}
+
+// parseEventQuery reads the container, type, since, until, limit and cursor parameters
+func parseEventQuery(r *http.Request) (storage.EventQuery, error) {
+	params := r.URL.Query()
+	query := storage.EventQuery{
+		Container: params.Get("container"),
+		Limit:     defaultEventLimit,
+	}
+
+	for _, t := range strings.Split(params.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			query.Types = append(query.Types, t)
+		}
+	}
+
+	now := time.Now()
+	if sinceParam := params.Get("since"); sinceParam != "" {
+		since, err := parseTimeParam(sinceParam, now)
+		if err != nil {
+			return query, fmt.Errorf("invalid since: %v", err)
+		}
+		query.Since = since
+	}
+	if untilParam := params.Get("until"); untilParam != "" {
+		until, err := parseTimeParam(untilParam, now)
+		if err != nil {
+			return query, fmt.Errorf("invalid until: %v", err)
+		}
+		query.Until = until
+	}
+
+	if limitParam := params.Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 || limit > maxEventLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", maxEventLimit)
+		}
+		query.Limit = limit
+	}
+
+	if cursorParam := params.Get("cursor"); cursorParam != "" {
+		cursor, err := strconv.ParseUint(cursorParam, 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("invalid cursor")
+		}
+		query.Before = cursor
+	}
+
+	return query, nil
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gocontainerops/internal/container"
+)
+
+// ForecastReport holds the latest exhaustion forecasts
+type ForecastReport struct {
+	UpdatedAt      time.Time            `json:"updated_at"`
+	HorizonSeconds float64              `json:"horizon_seconds"`
+	Alerting       int                  `json:"alerting"`
+	Forecasts      []container.Forecast `json:"forecasts"`
+}
+
+// HandleForecasts handles the /api/forecasts endpoint. It returns the predicted
+// time until each container reaches its memory limit and until Docker fills the
+// data-root filesystem. kind=memory|disk and alerting=true filter the list; with
+// fail_on_alert=true the response is 503 while any forecast is alerting, so the
+// endpoint can back an external alert check.
+func (h *Handler) HandleForecasts(w http.ResponseWriter, r *http.Request) {
+	if h.Forecasts == nil {
+		http.Error(w, "Forecasts are disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if kind != "" && kind != "memory" && kind != "disk" {
+		http.Error(w, "kind must be memory or disk", http.StatusBadRequest)
+		return
+	}
+	alertingOnly := r.URL.Query().Get("alerting") == "true"
+
+	forecasts, updated := h.Forecasts.Latest()
+	report := ForecastReport{
+		UpdatedAt:      updated,
+		HorizonSeconds: h.Forecasts.Horizon.Seconds(),
+		Forecasts:      []container.Forecast{},
+	}
+	for _, forecast := range forecasts {
+		if (kind != "" && forecast.Kind != kind) || (alertingOnly && !forecast.Alerting) {
+			continue
+		}
+		if forecast.Alerting {
+			report.Alerting++
+		}
+		report.Forecasts = append(report.Forecasts, forecast)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Alerting > 0 && r.URL.Query().Get("fail_on_alert") == "true" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -6,21 +6,44 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
 
+	"gocontainerops/internal/config"
 	"gocontainerops/internal/container"
 	"gocontainerops/internal/docker"
+	"gocontainerops/internal/host"
+	"gocontainerops/internal/monitor"
+	"gocontainerops/internal/registry"
+	"gocontainerops/internal/security"
 	"gocontainerops/internal/storage"
+	"gocontainerops/internal/stream"
 )
 
 // Handler struct to hold dependencies
 type Handler struct {
 	DockerService docker.DockerService
 	HistoryStore  storage.HistoryStore
+	CrashLoops    *container.CrashLoopDetector
+	Anomalies     *container.AnomalyDetector
+	Contention    *container.ContentionDetector
+	Config        config.Config
+	Secrets       *security.SecretScanner
+	Updates       *registry.Checker
+	Host          *host.Collector
+	Stats         *monitor.StatsManager
+	Watcher       *monitor.Watcher
+	Forecasts     *monitor.ForecastTracker
+
+	hostMu   sync.Mutex
+	hostName string
+
+	streamOnce sync.Once
+	stream     *stream.Hub
 }
 
 
@@ -46,6 +69,18 @@ func (h *Handler) HandleLogs(w http.ResponseWriter, r *http.Request) {
 
 	follow := r.URL.Query().Get("follow") == "true"
 
+	// Docker sends one frame per line, so the redactor carries private key blocks across frames
+	var redactor *security.LogRedactor
+	if h.Secrets != nil {
+		redactor = h.Secrets.NewLogRedactor(h.redactionMode(r))
+	}
+	redact := func(content []byte) []byte {
+		if redactor == nil {
+			return content
+		}
+		return []byte(redactor.Redact(string(content)))
+	}
+
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -87,7 +122,7 @@ func (h *Handler) HandleLogs(w http.ResponseWriter, r *http.Request) {
 			if err != nil {
 				break
 			}
-			fmt.Fprintf(w, "data: %s\n\n", string(content))
+			fmt.Fprintf(w, "data: %s\n\n", string(redact(content)))
 			flusher.Flush()
 		}
 	} else {
@@ -104,7 +139,7 @@ func (h *Handler) HandleLogs(w http.ResponseWriter, r *http.Request) {
 			if err != nil {
 				break
 			}
-			w.Write(content)
+			w.Write(redact(content))
 		}
 	}
 }
@@ -118,9 +153,17 @@ func (h *Handler) HandleStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	containers = filterBySelector(containers, selector)
+
 	searchQuery := r.URL.Query().Get("search")
 	imageFilter := r.URL.Query().Get("image")
 	statusFilter := r.URL.Query().Get("status")
+	projectFilter := r.URL.Query().Get("project")
 
 	var filteredContainers []types.Container
 	for _, c := range containers {
@@ -140,6 +183,13 @@ func (h *Handler) HandleStats(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		// Filter by compose project
+		if projectFilter != "" {
+			if c.Labels[container.ComposeProjectLabel] != projectFilter {
+				match = false
+			}
+		}
+
 		// Filter by search query (name)
 		if searchQuery != "" {
 			name := "unknown"
@@ -156,64 +206,10 @@ func (h *Handler) HandleStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	var results []container.ContainerData
-	var wg sync.WaitGroup
-	var mutex sync.Mutex
-
-	// Fetch stats for each container concurrently
-	for _, c := range filteredContainers {
-		wg.Add(1)
-		go func(c types.Container) {
-			defer wg.Done()
-
-			// Inspect to get RestartCount
-			jsonInfo, err := h.DockerService.ContainerInspect(ctx, c.ID)
-			restartCount := 0
-			if err == nil {
-				restartCount = jsonInfo.RestartCount
-			} else {
-				log.Printf("Error inspecting container %s: %v", c.ID[:10], err)
-			}
-
-			// We request a one-time stream snapshot (stream: false)
-			statsReader, err := h.DockerService.ContainerStats(ctx, c.ID) // Renamed to statsReader for clarity
-            if err != nil {
-                log.Printf("Error getting stats for %s: %v", c.ID[:10], err)
-                return
-            }
-            defer statsReader.Close() // Use statsReader directly, remove .Body
-
-            var stats types.StatsJSON
-            // Decode directly from the statsReader, remove .Body
-            if err := json.NewDecoder(statsReader).Decode(&stats); err != nil { 
-                return
-            }
-
-			data := container.ProcessStats(c, &stats, restartCount)
-
-			mutex.Lock()
-			results = append(results, data)
-		mutex.Unlock()
-		
-		// Store metric snapshot in history
-		if h.HistoryStore != nil {
-			h.HistoryStore.AddMetric(storage.MetricSnapshot{
-				ContainerID: data.ID,
-				Timestamp:   time.Now(),
-				CPUPercent:  data.CPUPercent,
-				MemUsage:    data.MemUsage,
-				MemPercent:  data.MemPercent,
-				NetInput:    data.NetInput,
-				NetOutput:   data.NetOutput,
-			})
-		}
-	}(c)
-}
-
-wg.Wait()
+	results := h.collectContainerData(ctx, filteredContainers, true)
 
-w.Header().Set("Content-Type", "application/json")
-json.NewEncoder(w).Encode(results)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
 }
 
 // HandleAggregateMetrics handles the /api/metrics/aggregate endpoint
@@ -225,48 +221,189 @@ func (h *Handler) HandleAggregateMetrics(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := h.collectContainerData(ctx, filterBySelector(containers, selector), false)
+	// Aggregate per group when requested (e.g. group_by=label:team)
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		groups, err := container.CalculateGroupedAggregateMetrics(results, groupBy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Each group reports its own crash loops and share of the host
+		members, _ := container.GroupContainers(results, groupBy)
+		for i := range groups {
+			groups[i].Metrics.CrashLoopingContainers = h.crashLooping(members[groups[i].Group])
+			groups[i].Metrics.Host = h.hostCapacity(members[groups[i].Group])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(groups)
+		return
+	}
+
+	// Calculate aggregate metrics
+	aggregateMetrics := container.CalculateAggregateMetrics(results)
+	aggregateMetrics.CrashLoopingContainers = h.crashLooping(results)
+	aggregateMetrics.Host = h.hostCapacity(results)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aggregateMetrics)
+}
+
+// collectContainerData inspects and fetches stats for each container concurrently.
+// When recordHistory is set, a metric snapshot is stored for every container.
+func (h *Handler) collectContainerData(ctx context.Context, containers []types.Container, recordHistory bool) []container.ContainerData {
 	var results []container.ContainerData
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
 
-	// Fetch stats for each container concurrently
+	host := h.host(ctx)
+
 	for _, c := range containers {
 		wg.Add(1)
 		go func(c types.Container) {
 			defer wg.Done()
 
-			jsonInfo, err := h.DockerService.ContainerInspect(ctx, c.ID)
-			restartCount := 0
-			if err == nil {
-				restartCount = jsonInfo.RestartCount
+			restartCount, health := h.lifecycle(ctx, c.ID)
+
+			// Serve the latest streamed sample when available; otherwise fall back to a
+			// one-time snapshot (stream: false), which makes the daemon wait about a second
+			stats, ok := h.streamedStats(c)
+			if !ok {
+				statsReader, err := h.DockerService.ContainerStats(ctx, c.ID)
+				if err != nil {
+					log.Printf("Error getting stats for %s: %v", c.ID[:10], err)
+					return
+				}
+				defer statsReader.Close()
+
+				stats = &types.StatsJSON{}
+				if err := json.NewDecoder(statsReader).Decode(stats); err != nil {
+					return
+				}
 			}
 
-			statsReader, err := h.DockerService.ContainerStats(ctx, c.ID)
-			if err != nil {
-				return
-			}
-			defer statsReader.Close()
-
-			var stats types.StatsJSON
-			if err := json.NewDecoder(statsReader).Decode(&stats); err != nil {
-				return
-			}
-
-			data := container.ProcessStats(c, &stats, restartCount)
+			data := container.ProcessStats(c, stats, restartCount)
+			data.Health = h.redactHealth(health, "")
+			data.Host = host
 
 			mutex.Lock()
 			results = append(results, data)
 			mutex.Unlock()
+
+			// Store metric snapshot in history
+			if recordHistory && h.HistoryStore != nil {
+				h.HistoryStore.AddMetric(storage.MetricSnapshot{
+					ContainerID: data.ID,
+					Timestamp:   time.Now(),
+					CPUPercent:  data.CPUPercent,
+					MemUsage:    data.MemUsage,
+					MemLimit:    data.MemLimit,
+					MemPercent:  data.MemPercent,
+					NetInput:    data.NetInput,
+					NetOutput:   data.NetOutput,
+				})
+			}
 		}(c)
 	}
 
 	wg.Wait()
 
-	// Calculate aggregate metrics
-	aggregateMetrics := container.CalculateAggregateMetrics(results)
+	return results
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(aggregateMetrics)
+// hostCapacity expresses container totals against real host capacity rather than
+// summed container limits, when the host collector has a sample
+func (h *Handler) hostCapacity(results []container.ContainerData) *container.HostCapacity {
+	if h.Host == nil {
+		return nil
+	}
+	snapshot, ok := h.Host.Latest()
+	if !ok {
+		return nil
+	}
+	return container.CalculateHostCapacity(results, snapshot.CPUCores, snapshot.MemTotal)
+}
+
+// lifecycle returns a container's restart count and health check state, from the
+// watcher when it already follows the container and by inspecting it otherwise
+func (h *Handler) lifecycle(ctx context.Context, containerID string) (int, *container.HealthInfo) {
+	if h.Watcher != nil {
+		if restartCount, health, ok := h.Watcher.Lifecycle(containerID[:12]); ok {
+			return restartCount, health
+		}
+	}
+
+	info, err := h.DockerService.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Error inspecting container %s: %v", containerID[:10], err)
+		return 0, nil
+	}
+	var health *container.HealthInfo
+	if info.State != nil {
+		health = container.ExtractHealth(info.State.Health)
+	}
+	return info.RestartCount, health
+}
+
+// redactionMode returns the mode used to redact secrets in container output: the
+// configured one, which admins may override with ?redact=
+func (h *Handler) redactionMode(r *http.Request) string {
+	if mode := r.URL.Query().Get("redact"); mode != "" && h.isElevated(r) {
+		return mode
+	}
+	return h.Secrets.RedactionMode()
+}
+
+// redactHealth returns a copy of a health check state with secrets in the probe
+// output redacted like container logs. An empty mode uses the configured one.
+func (h *Handler) redactHealth(health *container.HealthInfo, mode string) *container.HealthInfo {
+	if health == nil || h.Secrets == nil {
+		return health
+	}
+	if mode == "" {
+		mode = h.Secrets.RedactionMode()
+	}
+
+	redacted := *health
+	redacted.Log = make([]container.HealthProbe, len(health.Log))
+	for i, probe := range health.Log {
+		probe.Output = h.Secrets.RedactWith(probe.Output, mode)
+		redacted.Log[i] = probe
+	}
+	return &redacted
+}
+
+// streamedStats returns the stats manager's latest sample for a running container
+func (h *Handler) streamedStats(c types.Container) (*types.StatsJSON, bool) {
+	if h.Stats == nil || c.State != "running" {
+		return nil, false
+	}
+	return h.Stats.Latest(c.ID)
+}
+
+// host returns the name of the Docker host, looked up from the daemon until a
+// lookup succeeds and cached from then on
+func (h *Handler) host(ctx context.Context) string {
+	h.hostMu.Lock()
+	defer h.hostMu.Unlock()
+
+	if h.hostName == "" {
+		info, err := h.DockerService.Info(ctx)
+		if err != nil {
+			log.Printf("Error getting docker info: %v", err)
+			return ""
+		}
+		h.hostName = info.Name
+	}
+	return h.hostName
 }
 
 // HandleContainerHistory handles the /api/history/:id endpoint
@@ -277,6 +414,10 @@ func (h *Handler) HandleContainerHistory(w http.ResponseWriter, r *http.Request)
 	}
 
 	id := strings.TrimPrefix(r.URL.Path, "/api/history/")
+	if summaryID, found := strings.CutSuffix(id, "/summary"); found {
+		h.handleMetricSummary(w, r, summaryID)
+		return
+	}
 	
 	// Get metrics from last hour by default
 	since := time.Now().Add(-1 * time.Hour)
@@ -296,20 +437,44 @@ func (h *Handler) HandleContainerHistory(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(metrics)
 }
 
-// HandleEvents handles the /api/events endpoint
+// HandleEvents handles the /api/events endpoint, newest first.
+// Supports container, selector, type (comma-separated), since, until, limit and cursor filters.
 func (h *Handler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 	if h.HistoryStore == nil {
 		http.Error(w, "History store not available", http.StatusServiceUnavailable)
 		return
 	}
 
-	limit := 100
-	events, err := h.HistoryStore.GetAllEvents(limit)
+	query, err := parseEventQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.IDs, err = h.selectedContainerIDs(r.Context(), selector); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	events, err := h.HistoryStore.QueryEvents(query)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// A full page may have more events behind it; point at the next page
+	if len(events) == query.Limit {
+		cursor := strconv.FormatUint(events[len(events)-1].ID, 10)
+		next := r.URL.Query()
+		next.Set("cursor", cursor)
+		w.Header().Set("X-Next-Cursor", cursor)
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, next.Encode()))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(events)
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// ContainerHealth holds the health check state and transition history of a container
+type ContainerHealth struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Health      *container.HealthInfo    `json:"health"`
+	Transitions []storage.ContainerEvent `json:"transitions"`
+}
+
+// HandleHealth handles the /api/health/:id endpoint. Probe output is redacted
+// like container logs.
+func (h *Handler) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	id := strings.TrimPrefix(r.URL.Path, "/api/health/")
+
+	info, err := h.DockerService.ContainerInspect(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	result := ContainerHealth{
+		ID:          info.ID[:12],
+		Name:        strings.TrimPrefix(info.Name, "/"),
+		Transitions: []storage.ContainerEvent{},
+	}
+	if info.State != nil {
+		result.Health = container.ExtractHealth(info.State.Health)
+		if h.Secrets != nil {
+			result.Health = h.redactHealth(result.Health, h.redactionMode(r))
+		}
+	}
+
+	if h.HistoryStore != nil {
+		events, err := h.HistoryStore.GetEvents(result.ID, 1000)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, event := range events {
+			if event.EventType == "health_status" {
+				result.Transitions = append(result.Transitions, event)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
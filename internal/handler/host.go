@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HandleHost handles the /api/host and /api/host/history endpoints
+func (h *Handler) HandleHost(w http.ResponseWriter, r *http.Request) {
+	if h.Host == nil {
+		http.Error(w, "Host metrics are disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/host"), "/")
+
+	switch path {
+	case "":
+		snapshot, ok := h.Host.Latest()
+		if !ok {
+			var err error
+			snapshot, err = h.Host.Collect(time.Now())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(snapshot)
+	case "history":
+		if h.HistoryStore == nil {
+			http.Error(w, "History store not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		// Report the last hour by default
+		since, until, err := parseTimeRange(r, time.Hour)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		metrics, err := h.HistoryStore.GetHostMetrics(since, until)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metrics)
+	default:
+		http.NotFound(w, r)
+	}
+}
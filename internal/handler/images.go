@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/inventory"
+)
+
+// defaultLayerThresholdMB is the layer size above which a layer is flagged as oversized
+const defaultLayerThresholdMB = 100
+
+// HandleImages handles the /api/images, /api/images/outdated and
+// /api/images/:id/history endpoints
+func (h *Handler) HandleImages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/images"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "":
+		h.handleImageList(w, r)
+	case path == "outdated":
+		h.handleOutdatedImages(w, r)
+	case len(parts) == 2 && parts[1] == "history":
+		h.handleImageHistory(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleImageList returns every local image with the containers that use it.
+// dangling=true|false restricts the list to untagged or tagged images, and a
+// selector to images used by a container whose labels match it.
+func (h *Handler) handleImageList(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.DockerService.ImageList(ctx, types.ImageListOptions{SharedSize: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	inventoryList := inventory.BuildImageInventory(images, filterBySelector(containers, selector))
+
+	if len(selector) > 0 {
+		used := make([]inventory.ImageInfo, 0, len(inventoryList))
+		for _, img := range inventoryList {
+			if len(img.Containers) > 0 {
+				used = append(used, img)
+			}
+		}
+		inventoryList = used
+	}
+
+	if dangling := r.URL.Query().Get("dangling"); dangling != "" {
+		want := dangling == "true"
+		filtered := make([]inventory.ImageInfo, 0, len(inventoryList))
+		for _, img := range inventoryList {
+			if img.Dangling == want {
+				filtered = append(filtered, img)
+			}
+		}
+		inventoryList = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(inventoryList)
+}
+
+// handleOutdatedImages returns containers still running an image that has since
+// been replaced locally under the same tag
+func (h *Handler) handleOutdatedImages(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.DockerService.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	containers = filterBySelector(containers, selector)
+
+	// Containers whose tag has moved are listed by image ID; look up the reference they use
+	references := make(map[string]string)
+	for _, c := range containers {
+		if !strings.HasPrefix(c.Image, "sha256:") {
+			continue
+		}
+		info, err := h.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Printf("Error inspecting container %s: %v", c.ID[:12], err)
+			continue
+		}
+		if info.Config != nil {
+			references[c.ID] = info.Config.Image
+		}
+	}
+
+	outdated := inventory.FindOutdatedContainers(images, containers, references)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outdated)
+}
+
+// handleImageHistory returns the layers of an image, flagging those of at least
+// threshold_mb megabytes (default 100)
+func (h *Handler) handleImageHistory(w http.ResponseWriter, r *http.Request, imageID string) {
+	thresholdMB := defaultLayerThresholdMB
+	if thresholdParam := r.URL.Query().Get("threshold_mb"); thresholdParam != "" {
+		t, err := strconv.Atoi(thresholdParam)
+		if err != nil || t < 0 {
+			http.Error(w, "threshold_mb must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		thresholdMB = t
+	}
+
+	history, err := h.DockerService.ImageHistory(context.Background(), imageID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	layers := inventory.BuildLayers(history, int64(thresholdMB)*1024*1024)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(layers)
+}
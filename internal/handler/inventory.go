@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+
+	"gocontainerops/internal/inventory"
+)
+
+// HandleVolumes handles the /api/volumes endpoint. With a selector, only volumes
+// mounted by a container whose labels match it are listed.
+func (h *Handler) HandleVolumes(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	volumes, err := h.DockerService.VolumeList(ctx, volume.ListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Volume sizes are only reported by system df, which walks every volume
+	usage, err := h.DockerService.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := inventory.BuildVolumeInventory(volumes.Volumes, usage.Volumes, filterBySelector(containers, selector))
+	if len(selector) > 0 {
+		mounted := make([]inventory.VolumeInfo, 0, len(result))
+		for _, v := range result {
+			if len(v.Containers) > 0 {
+				mounted = append(mounted, v)
+			}
+		}
+		result = mounted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleNetworks handles the /api/networks endpoint. With a selector, only networks
+// a container whose labels match it is attached to are listed.
+func (h *Handler) HandleNetworks(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	networks, err := h.DockerService.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := inventory.BuildNetworkInventory(networks, filterBySelector(containers, selector))
+	if len(selector) > 0 {
+		attached := make([]inventory.NetworkInfo, 0, len(result))
+		for _, n := range result {
+			if len(n.Containers) > 0 {
+				attached = append(attached, n)
+			}
+		}
+		result = attached
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleDiskUsage handles the /api/system/df and /api/system/df/history endpoints
+func (h *Handler) HandleDiskUsage(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/system/df"), "/")
+
+	switch path {
+	case "":
+		usage, err := h.DockerService.DiskUsage(context.Background(), types.DiskUsageOptions{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(inventory.SummarizeDiskUsage(usage, time.Now()))
+	case "history":
+		if h.HistoryStore == nil {
+			http.Error(w, "History store not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		// Report the last 7 days by default
+		since, until, err := parseTimeRange(r, 7*24*time.Hour)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		history, err := h.HistoryStore.GetDiskUsageHistory(since, until)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+)
+
+// parseTimeRange reads the since and until query parameters.
+// Each accepts either a duration relative to now ("1h", "30m") or an RFC3339 timestamp.
+// A missing until means "now"; a missing since defaults to now minus defaultWindow.
+func parseTimeRange(r *http.Request, defaultWindow time.Duration) (time.Time, time.Time, error) {
+	now := time.Now()
+	since := now.Add(-defaultWindow)
+	until := now
+
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		t, err := parseTimeParam(sinceParam, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since: %v", err)
+		}
+		since = t
+	}
+
+	if untilParam := r.URL.Query().Get("until"); untilParam != "" {
+		t, err := parseTimeParam(untilParam, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid until: %v", err)
+		}
+		until = t
+	}
+
+	if until.Before(since) {
+		return time.Time{}, time.Time{}, fmt.Errorf("until must not be before since")
+	}
+
+	return since, until, nil
+}
+
+// parseTimeParam parses a relative duration or an RFC3339 timestamp
+func parseTimeParam(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseSelector reads the selector query parameter (e.g. "env=prod,team in (a,b),!canary")
+func parseSelector(r *http.Request) (container.Selector, error) {
+	selector, err := container.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %v", err)
+	}
+	return selector, nil
+}
+
+// filterBySelector keeps the containers whose labels match the selector
+func filterBySelector(containers []types.Container, selector container.Selector) []types.Container {
+	if len(selector) == 0 {
+		return containers
+	}
+
+	var result []types.Container
+	for _, c := range containers {
+		if selector.Matches(c.Labels) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
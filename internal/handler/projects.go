@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+)
+
+// ProjectSummary holds a compose project with its services and aggregate metrics
+type ProjectSummary struct {
+	Name     string                     `json:"name"`
+	Services []ServiceSummary           `json:"services"`
+	Metrics  container.AggregateMetrics `json:"metrics"`
+}
+
+// ServiceSummary holds the containers of a single compose service
+type ServiceSummary struct {
+	Name       string   `json:"name"`
+	Containers int      `json:"containers"`
+	Running    int      `json:"running"`
+	DependsOn  []string `json:"depends_on,omitempty"`
+}
+
+// ProjectActionResult reports the outcome of a project action on one container
+type ProjectActionResult struct {
+	Service     string `json:"service"`
+	ContainerID string `json:"container_id"`
+	Name        string `json:"name"`
+	Action      string `json:"action"` // "stop", "start"
+	Error       string `json:"error,omitempty"`
+}
+
+// HandleProjects handles the /api/projects, /api/projects/:name and
+// /api/projects/:name/{restart|stop} endpoints
+func (h *Handler) HandleProjects(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/projects"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		h.handleProjectList(w, r, "")
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		h.handleProjectList(w, r, parts[0])
+	case len(parts) == 2 && (parts[1] == "restart" || parts[1] == "stop"):
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleProjectAction(w, r, parts[0], parts[1])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleProjectList returns every compose project, or only the named one
+func (h *Handler) handleProjectList(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := context.Background()
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	byProject := groupByProject(filterBySelector(containers, selector))
+	if name != "" {
+		if _, exists := byProject[name]; !exists {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		byProject = map[string][]types.Container{name: byProject[name]}
+	}
+
+	projects := make([]ProjectSummary, 0, len(byProject))
+	for project, members := range byProject {
+		results := h.collectContainerData(ctx, members, false)
+		projects = append(projects, ProjectSummary{
+			Name:     project,
+			Services: summarizeServices(members),
+			Metrics:  container.CalculateAggregateMetrics(results),
+		})
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if name != "" {
+		json.NewEncoder(w).Encode(projects[0])
+		return
+	}
+	json.NewEncoder(w).Encode(projects)
+}
+
+// handleProjectAction stops or restarts every container of a project.
+// Containers are stopped in reverse depends_on order and started in depends_on order.
+func (h *Handler) handleProjectAction(w http.ResponseWriter, r *http.Request, name, action string) {
+	if !h.isElevated(r) {
+		http.Error(w, "Project actions require the admin role", http.StatusForbidden)
+		return
+	}
+
+	ctx := context.Background()
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	members := groupByProject(containers)[name]
+	if len(members) == 0 {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	dependencies := make(map[string][]string)
+	byService := make(map[string][]types.Container)
+	for _, c := range members {
+		compose := container.ParseCompose(c.Labels)
+		dependencies[compose.Service] = compose.DependsOn
+		byService[compose.Service] = append(byService[compose.Service], c)
+	}
+
+	order, err := container.ServiceStartOrder(dependencies)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	var results []ProjectActionResult
+
+	// Stop dependents before the services they depend on
+	for i := len(order) - 1; i >= 0; i-- {
+		for _, c := range byService[order[i]] {
+			if c.State != "running" && c.State != "restarting" && c.State != "paused" {
+				continue
+			}
+			results = append(results, h.runProjectAction(ctx, order[i], c, "stop"))
+		}
+	}
+
+	if action == "restart" {
+		for _, service := range order {
+			for _, c := range byService[service] {
+				results = append(results, h.runProjectAction(ctx, service, c, "start"))
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// runProjectAction starts or stops a single container and reports the outcome
+func (h *Handler) runProjectAction(ctx context.Context, service string, c types.Container, action string) ProjectActionResult {
+	result := ProjectActionResult{
+		Service:     service,
+		ContainerID: c.ID[:12],
+		Name:        containerName(c),
+		Action:      action,
+	}
+
+	var err error
+	if action == "stop" {
+		err = h.DockerService.ContainerStop(ctx, c.ID)
+	} else {
+		err = h.DockerService.ContainerStart(ctx, c.ID)
+	}
+	if err != nil {
+		log.Printf("Error running %s on container %s: %v", action, result.ContainerID, err)
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// groupByProject groups compose-managed containers by project name
+func groupByProject(containers []types.Container) map[string][]types.Container {
+	result := make(map[string][]types.Container)
+	for _, c := range containers {
+		if project := c.Labels[container.ComposeProjectLabel]; project != "" {
+			result[project] = append(result[project], c)
+		}
+	}
+	return result
+}
+
+// summarizeServices counts containers per compose service
+func summarizeServices(containers []types.Container) []ServiceSummary {
+	byName := make(map[string]*ServiceSummary)
+	for _, c := range containers {
+		compose := container.ParseCompose(c.Labels)
+		service, exists := byName[compose.Service]
+		if !exists {
+			service = &ServiceSummary{Name: compose.Service, DependsOn: compose.DependsOn}
+			byName[compose.Service] = service
+		}
+		service.Containers++
+		if c.State == "running" {
+			service.Running++
+		}
+	}
+
+	result := make([]ServiceSummary, 0, len(byName))
+	for _, service := range byName {
+		result = append(result, *service)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// containerName returns the container's primary name without the leading slash
+func containerName(c types.Container) string {
+	if len(c.Names) > 0 {
+		return c.Names[0][1:] // Remove leading slash
+	}
+	return "unknown"
+}
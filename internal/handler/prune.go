@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+
+	"gocontainerops/internal/inventory"
+	"gocontainerops/internal/storage"
+)
+
+// PruneResult reports what a prune removed, or would remove on a dry run
+type PruneResult struct {
+	inventory.PrunePlan
+	DryRun  bool           `json:"dry_run"`
+	Failed  []PruneFailure `json:"failed,omitempty"`
+	Skipped []PruneFailure `json:"skipped,omitempty"` // still in use, e.g. by a container created since planning
+}
+
+// PruneFailure is an object a prune could not remove
+type PruneFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// HandlePrune handles the POST /api/prune/{containers|images|volumes|networks|buildcache} endpoint.
+// dry_run=true lists what would be removed without removing it; otherwise the admin role is required.
+// until (duration or RFC3339), selector and all narrow or widen the selection.
+func (h *Handler) HandlePrune(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/prune"), "/")
+	switch kind {
+	case "containers", "images", "volumes", "networks", "buildcache":
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if !dryRun && !h.isElevated(r) {
+		http.Error(w, "Pruning requires the admin role; use dry_run=true to preview", http.StatusForbidden)
+		return
+	}
+
+	opts, err := h.parsePruneOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if kind == "buildcache" && len(opts.Selector) > 0 {
+		http.Error(w, "Build cache has no labels; selector is not supported", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	plan, err := h.planPrune(ctx, kind, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := PruneResult{PrunePlan: plan, DryRun: dryRun}
+	if !dryRun {
+		if kind == "buildcache" {
+			err = h.pruneBuildCache(ctx, opts, &result)
+		} else {
+			h.removePlanned(ctx, &result)
+		}
+	}
+
+	h.auditPrune(r, result, opts, err)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// parsePruneOptions reads the until, selector and all query parameters
+func (h *Handler) parsePruneOptions(r *http.Request) (inventory.PruneOptions, error) {
+	opts := inventory.PruneOptions{
+		ProtectedLabel: h.Config.ProtectedLabel,
+		All:            r.URL.Query().Get("all") == "true",
+	}
+
+	if untilParam := r.URL.Query().Get("until"); untilParam != "" {
+		until, err := parseTimeParam(untilParam, time.Now())
+		if err != nil {
+			return opts, fmt.Errorf("invalid until: %v", err)
+		}
+		opts.Until = until
+	}
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		return opts, err
+	}
+	opts.Selector = selector
+
+	return opts, nil
+}
+
+// planPrune lists the objects of a kind that a prune would remove
+func (h *Handler) planPrune(ctx context.Context, kind string, opts inventory.PruneOptions) (inventory.PrunePlan, error) {
+	if kind == "buildcache" {
+		usage, err := h.DockerService.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.BuildCacheObject}})
+		if err != nil {
+			return inventory.PrunePlan{}, err
+		}
+		return inventory.PlanBuildCachePrune(usage.BuildCache, opts), nil
+	}
+
+	// Sizes are only needed for containers; computing them is expensive
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true, Size: kind == "containers"})
+	if err != nil {
+		return inventory.PrunePlan{}, err
+	}
+
+	switch kind {
+	case "containers":
+		return inventory.PlanContainerPrune(containers, opts), nil
+	case "images":
+		images, err := h.DockerService.ImageList(ctx, types.ImageListOptions{SharedSize: true})
+		if err != nil {
+			return inventory.PrunePlan{}, err
+		}
+		return inventory.PlanImagePrune(images, containers, opts), nil
+	case "volumes":
+		// Volume sizes and reference counts are only reported by system df
+		usage, err := h.DockerService.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+		if err != nil {
+			return inventory.PrunePlan{}, err
+		}
+		return inventory.PlanVolumePrune(usage.Volumes, containers, opts), nil
+	default:
+		networks, err := h.DockerService.NetworkList(ctx, types.NetworkListOptions{})
+		if err != nil {
+			return inventory.PrunePlan{}, err
+		}
+		return inventory.PlanNetworkPrune(networks, containers, opts), nil
+	}
+}
+
+// removePlanned removes exactly the objects listed in the plan, so a real prune
+// never removes more than its dry run showed
+func (h *Handler) removePlanned(ctx context.Context, result *PruneResult) {
+	removed := make([]inventory.PruneItem, 0, len(result.Remove))
+	var bytes int64
+
+	for _, item := range result.Remove {
+		var err error
+		switch result.Kind {
+		case "containers":
+			err = h.DockerService.ContainerRemove(ctx, item.ID)
+		case "images":
+			err = h.removeImage(ctx, item)
+		case "volumes":
+			err = h.DockerService.VolumeRemove(ctx, item.ID)
+		case "networks":
+			err = h.DockerService.NetworkRemove(ctx, item.ID)
+		}
+		if isConflict(err) {
+			result.Skipped = append(result.Skipped, PruneFailure{ID: item.ID, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			log.Printf("Error pruning %s %s: %v", result.Kind, item.ID, err)
+			result.Failed = append(result.Failed, PruneFailure{ID: item.ID, Error: err.Error()})
+			continue
+		}
+		removed = append(removed, item)
+		bytes += item.Size
+	}
+
+	result.Remove = removed
+	result.Bytes = bytes
+}
+
+// removeImage removes an image without forcing. An image with several tags is removed
+// by untagging each in turn, the last removal deleting the image.
+func (h *Handler) removeImage(ctx context.Context, item inventory.PruneItem) error {
+	if len(item.Tags) <= 1 {
+		return h.DockerService.ImageRemove(ctx, item.ID)
+	}
+	for _, tag := range item.Tags {
+		if err := h.DockerService.ImageRemove(ctx, tag); err != nil {
+			return fmt.Errorf("removing tag %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// isConflict reports whether the daemon refused a removal because the object is in use.
+// errdefs.IsConflict does not see through fmt.Errorf wrapping.
+func isConflict(err error) bool {
+	var conflict errdefs.ErrConflict
+	return errors.As(err, &conflict)
+}
+
+// pruneBuildCache asks the daemon to prune the build cache, since records cannot be
+// removed one by one
+func (h *Handler) pruneBuildCache(ctx context.Context, opts inventory.PruneOptions, result *PruneResult) error {
+	args := filters.NewArgs()
+	if !opts.Until.IsZero() {
+		args.Add("until", strconv.FormatInt(opts.Until.Unix(), 10))
+	}
+
+	report, err := h.DockerService.BuildCachePrune(ctx, types.BuildCachePruneOptions{All: opts.All, Filters: args})
+	if err != nil {
+		return err
+	}
+
+	deleted := make(map[string]bool, len(report.CachesDeleted))
+	for _, id := range report.CachesDeleted {
+		deleted[id] = true
+	}
+
+	removed := []inventory.PruneItem{}
+	for _, item := range result.Remove {
+		if deleted[item.ID] {
+			removed = append(removed, item)
+			delete(deleted, item.ID)
+		}
+	}
+	// Records created between planning and pruning are still reported
+	for id := range deleted {
+		removed = append(removed, inventory.PruneItem{ID: id})
+	}
+
+	result.Remove = removed
+	result.Bytes = int64(report.SpaceReclaimed)
+	return nil
+}
+
+// auditPrune records a prune, including dry runs, in the audit log
+func (h *Handler) auditPrune(r *http.Request, result PruneResult, opts inventory.PruneOptions, pruneErr error) {
+	if h.HistoryStore == nil {
+		return
+	}
+
+	entry := storage.AuditEntry{
+		Timestamp: time.Now(),
+		Action:    "prune",
+		Target:    result.Kind,
+		Role:      h.callerRole(r),
+		Remote:    r.RemoteAddr,
+		DryRun:    result.DryRun,
+		Details: map[string]string{
+			"all":       strconv.FormatBool(opts.All),
+			"protected": strconv.Itoa(len(result.Protected)),
+			"failed":    strconv.Itoa(len(result.Failed)),
+			"skipped":   strconv.Itoa(len(result.Skipped)),
+		},
+		Bytes: result.Bytes,
+	}
+	if !opts.Until.IsZero() {
+		entry.Details["until"] = opts.Until.Format(time.RFC3339)
+	}
+	if selector := r.URL.Query().Get("selector"); selector != "" {
+		entry.Details["selector"] = selector
+	}
+	for _, item := range result.Remove {
+		entry.Items = append(entry.Items, item.ID)
+	}
+	if pruneErr != nil {
+		entry.Error = pruneErr.Error()
+	}
+
+	if err := h.HistoryStore.AddAudit(entry); err != nil {
+		log.Printf("Error recording audit entry: %v", err)
+	}
+}
+
+// HandleAuditLog handles the /api/audit endpoint. Requires the admin role.
+func (h *Handler) HandleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !h.isElevated(r) {
+		http.Error(w, "The audit log requires the admin role", http.StatusForbidden)
+		return
+	}
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit := 100
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		l, err := strconv.Atoi(limitParam)
+		if err != nil || l <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	entries, err := h.HistoryStore.GetAuditLog(limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
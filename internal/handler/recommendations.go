@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// ContainerRecommendation holds the right-sizing recommendations of one container
+type ContainerRecommendation struct {
+	ID             string                           `json:"id"`
+	Name           string                           `json:"name"`
+	Samples        int                              `json:"samples"`
+	FirstSample    time.Time                        `json:"first_sample,omitempty"`
+	LastSample     time.Time                        `json:"last_sample,omitempty"`
+	CoveredSeconds float64                          `json:"covered_seconds"`
+	Coverage       float64                          `json:"coverage"` // share of the lookback covered
+	ThrottledRatio float64                          `json:"throttled_ratio"`
+	OOMKills       int                              `json:"oom_kills"`
+	Memory         container.ResourceRecommendation `json:"memory"`
+	CPU            container.ResourceRecommendation `json:"cpu"`
+	MemorySavedMB  float64                          `json:"memory_saved_mb"`
+}
+
+// RecommendationReport holds the right-sizing recommendations of the running containers
+type RecommendationReport struct {
+	LookbackSeconds    float64                   `json:"lookback_seconds"`
+	TotalMemorySavedMB float64                   `json:"total_memory_saved_mb"`
+	Containers         []ContainerRecommendation `json:"containers"`
+}
+
+// ApplyResult reports the limits applied to a container
+type ApplyResult struct {
+	ContainerRecommendation
+	DryRun  bool     `json:"dry_run"`
+	Applied []string `json:"applied"` // "memory", "cpu"
+}
+
+// HandleRecommendations handles the /api/recommendations and
+// /api/recommendations/:id/apply endpoints
+func (h *Handler) HandleRecommendations(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/recommendations"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		h.handleRecommendationList(w, r)
+	case len(parts) == 2 && parts[1] == "apply":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleRecommendationApply(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleRecommendationList recommends limits for every running container.
+// flagged=true keeps only containers with a limit worth changing.
+func (h *Handler) handleRecommendationList(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	lookback, err := h.parseLookback(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	flaggedOnly := r.URL.Query().Get("flagged") == "true"
+	report := RecommendationReport{
+		LookbackSeconds: lookback.Seconds(),
+		Containers:      []ContainerRecommendation{},
+	}
+
+	for _, c := range filterBySelector(containers, selector) {
+		info, err := h.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Printf("Error inspecting container %s: %v", c.ID[:12], err)
+			continue
+		}
+
+		rec, err := h.recommend(info, lookback)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if flaggedOnly && !rec.Memory.Actionable() && !rec.CPU.Actionable() {
+			continue
+		}
+
+		report.TotalMemorySavedMB += rec.MemorySavedMB
+		report.Containers = append(report.Containers, rec)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleRecommendationApply applies a container's recommended limits through the
+// container update API. resource=memory|cpu limits the update to one resource.
+// Requires the admin role unless dry_run=true.
+func (h *Handler) handleRecommendationApply(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := context.Background()
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if !dryRun && !h.isElevated(r) {
+		http.Error(w, "Applying recommendations requires the admin role", http.StatusForbidden)
+		return
+	}
+
+	resource := r.URL.Query().Get("resource")
+	if resource != "" && resource != "memory" && resource != "cpu" {
+		http.Error(w, "resource must be memory or cpu", http.StatusBadRequest)
+		return
+	}
+
+	lookback, err := h.parseLookback(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.DockerService.ContainerInspect(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	rec, err := h.recommend(info, lookback)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	limits := container.BuildInspectView(info).Resources
+	result := ApplyResult{ContainerRecommendation: rec, DryRun: dryRun, Applied: []string{}}
+	var update dockercontainer.UpdateConfig
+
+	if (resource == "" || resource == "memory") && rec.Memory.Actionable() {
+		update.Memory = int64(rec.Memory.Recommended * 1024 * 1024)
+		// Keep the swap allowance; a swap limit below the new memory limit is rejected
+		if limits.MemorySwap > 0 && limits.Memory > 0 {
+			update.MemorySwap = update.Memory + limits.MemorySwap - limits.Memory
+		}
+		result.Applied = append(result.Applied, "memory")
+	}
+	if (resource == "" || resource == "cpu") && rec.CPU.Actionable() {
+		// NanoCPUs and a CFS quota cannot both be set; keep whichever the container uses
+		if limits.CPUQuota > 0 {
+			period := limits.CPUPeriod
+			if period == 0 {
+				period = 100000
+			}
+			update.CPUQuota = int64(rec.CPU.Recommended * float64(period))
+		} else {
+			update.NanoCPUs = int64(rec.CPU.Recommended * 1e9)
+		}
+		result.Applied = append(result.Applied, "cpu")
+	}
+
+	if len(result.Applied) == 0 {
+		for _, name := range []string{"memory", "cpu"} {
+			res := rec.Memory
+			if name == "cpu" {
+				res = rec.CPU
+			}
+			if (resource == "" || resource == name) && res.Status == "insufficient_history" {
+				http.Error(w, "Not enough history to change the "+name+" limit: "+res.Reason, http.StatusConflict)
+				return
+			}
+		}
+		http.Error(w, "No recommendation to apply", http.StatusConflict)
+		return
+	}
+
+	if !dryRun {
+		if err := h.updateResources(ctx, r, info, update, "recommendation"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// recommend builds the right-sizing recommendation of a container from its history
+func (h *Handler) recommend(info types.ContainerJSON, lookback time.Duration) (ContainerRecommendation, error) {
+	view := container.BuildInspectView(info)
+	now := time.Now()
+	since := now.Add(-lookback)
+
+	// Raw metrics only cover the last few hours; the sampler's rollups span the lookback
+	summary, err := h.HistoryStore.GetRollupSummary(view.ID, since, now)
+	if err != nil {
+		return ContainerRecommendation{}, err
+	}
+
+	usage := container.UsageProfile{
+		Samples:  summary.Samples,
+		Coverage: summary.Coverage,
+	}
+	if summary.CPUPeak != nil && summary.MemPeak != nil {
+		usage.CPUP95 = summary.CPUPeak.P95
+		usage.CPUP99 = summary.CPUPeak.P99
+		usage.MemP95 = summary.MemPeak.P95
+		usage.MemP99 = summary.MemPeak.P99
+	}
+
+	exits, err := h.HistoryStore.GetExits(since, now)
+	if err != nil {
+		return ContainerRecommendation{}, err
+	}
+	for _, exit := range exits {
+		if exit.ContainerID == view.ID && exit.Reason == "oom_killed" {
+			usage.OOMKills++
+		}
+	}
+
+	// Throttling counters are cumulative since the container started
+	if h.Stats != nil {
+		if stats, ok := h.Stats.Latest(info.ID); ok {
+			throttling := stats.CPUStats.ThrottlingData
+			if throttling.Periods > 0 {
+				usage.ThrottledRatio = float64(throttling.ThrottledPeriods) / float64(throttling.Periods)
+			}
+		}
+	}
+
+	rec := ContainerRecommendation{
+		ID:             view.ID,
+		Name:           view.Name,
+		Samples:        summary.Samples,
+		FirstSample:    summary.FirstSample,
+		LastSample:     summary.LastSample,
+		CoveredSeconds: summary.LastSample.Sub(summary.FirstSample).Seconds(),
+		Coverage:       summary.Coverage,
+		ThrottledRatio: usage.ThrottledRatio,
+		OOMKills:       usage.OOMKills,
+		Memory:         container.RecommendMemory(view.Resources, usage),
+		CPU:            container.RecommendCPU(view.Resources, usage),
+	}
+	if rec.Memory.Status == "over_provisioned" {
+		rec.MemorySavedMB = rec.Memory.Current - rec.Memory.Recommended
+	}
+
+	return rec, nil
+}
+
+// updateResources applies new limits to a container and records the change in the
+// audit log. The watcher records the resulting configuration in config history
+// when the daemon reports the update.
+func (h *Handler) updateResources(ctx context.Context, r *http.Request, info types.ContainerJSON, update dockercontainer.UpdateConfig, source string) error {
+	err := h.DockerService.ContainerUpdate(ctx, info.ID, update)
+
+	entry := storage.AuditEntry{
+		Timestamp: time.Now(),
+		Action:    "update_resources",
+		Target:    strings.TrimPrefix(info.Name, "/"),
+		Role:      h.callerRole(r),
+		Remote:    r.RemoteAddr,
+		Details: map[string]string{
+			"source": source,
+		},
+		Items: []string{info.ID[:12]},
+	}
+	for field, value := range map[string]int64{
+		"memory":      update.Memory,
+		"memory_swap": update.MemorySwap,
+		"nano_cpus":   update.NanoCPUs,
+		"cpu_shares":  update.CPUShares,
+		"cpu_quota":   update.CPUQuota,
+		"cpu_period":  update.CPUPeriod,
+	} {
+		if value != 0 {
+			entry.Details[field] = strconv.FormatInt(value, 10)
+		}
+	}
+	if update.CpusetCpus != "" {
+		entry.Details["cpuset_cpus"] = update.CpusetCpus
+	}
+	if update.PidsLimit != nil {
+		entry.Details["pids_limit"] = strconv.FormatInt(*update.PidsLimit, 10)
+	}
+	if update.RestartPolicy.Name != "" {
+		entry.Details["restart_policy"] = update.RestartPolicy.Name
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if h.HistoryStore != nil {
+		if auditErr := h.HistoryStore.AddAudit(entry); auditErr != nil {
+			log.Printf("Error recording audit entry: %v", auditErr)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("updating %s: %v", entry.Target, err)
+	}
+	return nil
+}
+
+// parseLookback reads the lookback query parameter, defaulting to the configured lookback
+func (h *Handler) parseLookback(r *http.Request) (time.Duration, error) {
+	lookback := h.Config.RecommendationLookback
+	if lookbackParam := r.URL.Query().Get("lookback"); lookbackParam != "" {
+		d, err := time.ParseDuration(lookbackParam)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("lookback must be a positive duration such as 24h")
+		}
+		lookback = d
+	}
+	if lookback <= 0 {
+		lookback = 24 * time.Hour
+	}
+	return lookback, nil
+}
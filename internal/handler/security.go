@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/security"
+)
+
+// HandleSecurityAudit handles the /api/security/audit endpoint.
+// Running containers are audited by default; all=true includes stopped ones.
+// format=sarif returns the report as a SARIF 2.1.0 log.
+func (h *Handler) HandleSecurityAudit(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "sarif" {
+		http.Error(w, "format must be json or sarif", http.StatusBadRequest)
+		return
+	}
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	all := r.URL.Query().Get("all") == "true"
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: all})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	containers = filterBySelector(containers, selector)
+
+	var host security.HostSecurity
+	if info, err := h.DockerService.Info(ctx); err == nil {
+		host = security.ParseHostSecurity(info.SecurityOptions)
+	} else {
+		log.Printf("Error getting docker info: %v", err)
+	}
+
+	audits := make([]security.ContainerAudit, 0, len(containers))
+	for _, c := range containers {
+		info, err := h.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Printf("Error inspecting container %s: %v", c.ID[:10], err)
+			continue
+		}
+		audits = append(audits, security.AuditContainer(info, host))
+	}
+
+	report := security.NewReport(audits)
+
+	w.Header().Set("Content-Type", "application/json")
+	if format == "sarif" {
+		w.Header().Set("Content-Disposition", `attachment; filename="gocontainerops-audit.sarif"`)
+		json.NewEncoder(w).Encode(report.ToSARIF())
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleSecretScan handles the /api/security/secrets endpoint.
+// Environment variables are always scanned; logs=true also scans the last
+// `tail` lines (default 500) of each container's output.
+func (h *Handler) HandleSecretScan(w http.ResponseWriter, r *http.Request) {
+	if h.Secrets == nil {
+		http.Error(w, "Secret scanner not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.Background()
+
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scanLogs := r.URL.Query().Get("logs") == "true"
+	tail := r.URL.Query().Get("tail")
+	if tail == "" {
+		tail = "500"
+	}
+
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	containers = filterBySelector(containers, selector)
+
+	results := []security.ContainerSecrets{}
+	for _, c := range containers {
+		result := security.ContainerSecrets{
+			ID:       c.ID[:12],
+			Name:     containerName(c),
+			Image:    c.Image,
+			Findings: []security.SecretFinding{},
+		}
+
+		info, err := h.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			log.Printf("Error inspecting container %s: %v", c.ID[:10], err)
+		} else if info.Config != nil {
+			result.Findings = append(result.Findings, h.Secrets.ScanEnv(info.Config.Env)...)
+		}
+
+		if scanLogs {
+			reader, err := h.DockerService.ContainerLogs(ctx, c.ID, types.ContainerLogsOptions{
+				ShowStdout: true,
+				ShowStderr: true,
+				Tail:       tail,
+			})
+			if err != nil {
+				log.Printf("Error reading logs of %s: %v", c.ID[:10], err)
+			} else {
+				result.Findings = append(result.Findings, h.Secrets.ScanLines(demuxLogs(reader))...)
+				reader.Close()
+			}
+		}
+
+		if len(result.Findings) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// demuxLogs strips the 8-byte stream headers Docker prefixes to each log frame
+func demuxLogs(reader io.Reader) string {
+	var b strings.Builder
+	hdr := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, hdr); err != nil {
+			break
+		}
+		size := int(hdr[4])<<24 | int(hdr[5])<<16 | int(hdr[6])<<8 | int(hdr[7])
+		content := make([]byte, size)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			break
+		}
+		b.Write(content)
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/stream"
+)
+
+const (
+	// streamWriteTimeout disconnects clients that stop reading
+	streamWriteTimeout = 10 * time.Second
+
+	// streamKeepAlive is how often an idle stream sends a comment to keep proxies from closing it
+	streamKeepAlive = 15 * time.Second
+)
+
+// HandleStreamStats handles the /api/stream/stats Server-Sent Events endpoint.
+// Every client shares one collector; ids (names or IDs) and selector pick a subset
+// and interval sets how often updates are sent (never faster than the collector).
+func (h *Handler) HandleStreamStats(w http.ResponseWriter, r *http.Request) {
+	selector, err := parseSelector(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hub := h.statsHub()
+
+	interval := hub.Interval()
+	if intervalParam := r.URL.Query().Get("interval"); intervalParam != "" {
+		d, err := time.ParseDuration(intervalParam)
+		if err != nil || d <= 0 {
+			http.Error(w, "interval must be a positive duration such as 5s", http.StatusBadRequest)
+			return
+		}
+		interval = max(d, hub.Interval())
+	}
+
+	ids := make(map[string]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids[id] = true
+		}
+	}
+
+	filter := func(c container.ContainerData) bool {
+		if len(ids) > 0 && !ids[c.ID] && !ids[c.Name] {
+			return false
+		}
+		return selector.Matches(c.Labels)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	controller := http.NewResponseController(w)
+	subscription := hub.Subscribe(filter)
+	defer subscription.Close()
+
+	// A ticker drops ticks while a write blocks, so slow clients receive fewer,
+	// merged updates rather than a growing backlog
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastWrite := time.Now()
+	send := func(payload string) bool {
+		controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := fmt.Fprint(w, payload); err != nil {
+			return false
+		}
+		if err := controller.Flush(); err != nil {
+			return false
+		}
+		lastWrite = time.Now()
+		return true
+	}
+
+	if !send(fmt.Sprintf("retry: %d\n\n", interval.Milliseconds())) {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		update, ok := subscription.Next()
+		if ok && (update.Full || len(update.Changed) > 0 || len(update.Removed) > 0) {
+			data, err := json.Marshal(update)
+			if err != nil {
+				return
+			}
+			if !send(fmt.Sprintf("event: stats\ndata: %s\n\n", data)) {
+				return
+			}
+			continue
+		}
+
+		if time.Since(lastWrite) >= streamKeepAlive && !send(": keep-alive\n\n") {
+			return
+		}
+	}
+}
+
+// statsHub returns the shared stats collector, creating it on first use
+func (h *Handler) statsHub() *stream.Hub {
+	h.streamOnce.Do(func() {
+		interval := h.Config.StreamInterval
+		if interval <= 0 {
+			interval = 2 * time.Second
+		}
+		h.stream = stream.NewHub(interval, h.collectRunning)
+	})
+	return h.stream
+}
+
+// collectRunning gathers stats for every running container and records them in history
+func (h *Handler) collectRunning(ctx context.Context) ([]container.ContainerData, error) {
+	containers, err := h.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.collectContainerData(ctx, containers, true), nil
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/storage"
+)
+
+// RankedSummary is a fleet summary entry with the container's name
+type RankedSummary struct {
+	storage.MetricSummary
+	ContainerName string `json:"container_name,omitempty"`
+}
+
+// handleMetricSummary handles /api/history/:id/summary?window=1h
+func (h *Handler) handleMetricSummary(w http.ResponseWriter, r *http.Request, id string) {
+	window, err := parseWindow(r, time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	summary, err := h.HistoryStore.GetMetricSummary(id, now.Add(-window), now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// HandleFleetSummary handles the /api/metrics/summary endpoint, ranking containers
+// by p95 CPU (rank_by=cpu, the default) or memory (rank_by=memory) over the window
+func (h *Handler) HandleFleetSummary(w http.ResponseWriter, r *http.Request) {
+	if h.HistoryStore == nil {
+		http.Error(w, "History store not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	window, err := parseWindow(r, time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var p95 func(storage.MetricSummary) float64
+	switch rankBy := r.URL.Query().Get("rank_by"); rankBy {
+	case "", "cpu":
+		p95 = func(s storage.MetricSummary) float64 { return s.CPUPercent.P95 }
+	case "memory":
+		p95 = func(s storage.MetricSummary) float64 { return s.MemUsage.P95 }
+	default:
+		http.Error(w, "rank_by must be cpu or memory", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now()
+	summaries, err := h.HistoryStore.GetFleetSummary(now.Add(-window), now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return p95(summaries[i]) > p95(summaries[j])
+	})
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+
+	// History is keyed by ID; add names for display
+	names := make(map[string]string)
+	if containers, err := h.DockerService.ListContainers(context.Background(), types.ContainerListOptions{All: true}); err == nil {
+		for _, c := range containers {
+			names[c.ID[:12]] = containerName(c)
+		}
+	}
+
+	ranked := make([]RankedSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		ranked = append(ranked, RankedSummary{MetricSummary: summary, ContainerName: names[summary.ContainerID]})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranked)
+}
+
+// parseWindow reads the window query parameter as a duration such as "1h"
+func parseWindow(r *http.Request, fallback time.Duration) (time.Duration, error) {
+	windowParam := r.URL.Query().Get("window")
+	if windowParam == "" {
+		return fallback, nil
+	}
+
+	window, err := time.ParseDuration(windowParam)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("window must be a positive duration such as 1h")
+	}
+	return window, nil
+}
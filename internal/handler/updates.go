@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gocontainerops/internal/registry"
+)
+
+// UpdateReport is the response of the /api/updates endpoint
+type UpdateReport struct {
+	CheckedAt  time.Time               `json:"checked_at"`
+	Containers []registry.UpdateStatus `json:"containers"`
+}
+
+// HandleUpdates handles the /api/updates endpoint.
+// It returns containers whose image tag has a newer upstream digest or version tag;
+// all=true includes every checked container. POST runs a check immediately (admin only).
+func (h *Handler) HandleUpdates(w http.ResponseWriter, r *http.Request) {
+	if h.Updates == nil {
+		http.Error(w, "Update checks are disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !h.isElevated(r) {
+			http.Error(w, "Triggering an update check requires the admin role", http.StatusForbidden)
+			return
+		}
+		if err := h.Updates.Check(context.Background()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses, checkedAt := h.Updates.Statuses()
+
+	all := r.URL.Query().Get("all") == "true"
+	report := UpdateReport{CheckedAt: checkedAt, Containers: []registry.UpdateStatus{}}
+	for _, status := range statuses {
+		if all || status.UpdateAvailable || status.NewerTag != "" {
+			report.Containers = append(report.Containers, status)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
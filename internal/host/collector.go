@@ -0,0 +1,189 @@
+package host
+
+import (
+	"sync"
+	"time"
+
+	"gocontainerops/internal/storage"
+)
+
+// Snapshot holds host-level metrics at a point in time
+type Snapshot struct {
+	Timestamp    time.Time   `json:"timestamp"`
+	CPUCores     int         `json:"cpu_cores"`
+	CPUPercent   float64     `json:"cpu_percent"`   // 0-100 across all cores
+	MemTotal     float64     `json:"mem_total"`     // in MB
+	MemUsed      float64     `json:"mem_used"`      // in MB, excluding reclaimable cache
+	MemAvailable float64     `json:"mem_available"` // in MB
+	MemPercent   float64     `json:"mem_percent"`
+	SwapTotal    float64     `json:"swap_total"` // in MB
+	SwapUsed     float64     `json:"swap_used"`  // in MB
+	Load1        float64     `json:"load1"`
+	Load5        float64     `json:"load5"`
+	Load15       float64     `json:"load15"`
+	Filesystem   *Filesystem `json:"filesystem,omitempty"`
+	Disks        []DiskIO    `json:"disks"`
+	Network      []NetworkIO `json:"network"`
+	Pressure     *Pressure   `json:"pressure,omitempty"` // nil when the kernel lacks PSI
+}
+
+// Filesystem holds the usage of the filesystem backing Docker's data
+type Filesystem struct {
+	Path        string  `json:"path"`
+	Total       float64 `json:"total"`     // in MB
+	Used        float64 `json:"used"`      // in MB
+	Available   float64 `json:"available"` // in MB
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// DiskIO holds the throughput of a block device since the previous sample
+type DiskIO struct {
+	Device      string  `json:"device"`
+	ReadKBps    float64 `json:"read_kbps"`
+	WriteKBps   float64 `json:"write_kbps"`
+	Utilization float64 `json:"utilization"` // percent of time the device was busy
+}
+
+// NetworkIO holds the throughput of a physical interface since the previous sample
+type NetworkIO struct {
+	Interface string  `json:"interface"`
+	RxKBps    float64 `json:"rx_kbps"`
+	TxKBps    float64 `json:"tx_kbps"`
+}
+
+// Pressure holds pressure stall information for cpu, memory and io
+type Pressure struct {
+	CPU    PressureStats `json:"cpu"`
+	Memory PressureStats `json:"memory"`
+	IO     PressureStats `json:"io"`
+}
+
+// PressureStats holds the share of time tasks were stalled on a resource.
+// "some" counts time at least one task stalled, "full" time all non-idle tasks stalled.
+type PressureStats struct {
+	SomeAvg10  float64 `json:"some_avg10"`
+	SomeAvg60  float64 `json:"some_avg60"`
+	SomeAvg300 float64 `json:"some_avg300"`
+	FullAvg10  float64 `json:"full_avg10"`
+	FullAvg60  float64 `json:"full_avg60"`
+	FullAvg300 float64 `json:"full_avg300"`
+}
+
+// Collector reads host metrics from /proc and /sys. Rates are computed against
+// the previous sample, so the first sample reports zero throughput and CPU usage
+// since boot.
+type Collector struct {
+	ProcPath string // usually /proc, or the host's /proc mounted into this container
+	SysPath  string // usually /sys
+	DiskPath string // filesystem to report usage for
+
+	mu       sync.Mutex
+	previous *counters
+	latest   *Snapshot
+}
+
+// counters holds the cumulative values rates are derived from
+type counters struct {
+	timestamp time.Time
+	cpu       cpuTimes
+	disks     map[string]diskCounters
+	network   map[string]netCounters
+}
+
+// NewCollector creates a new host collector
+func NewCollector(procPath, sysPath, diskPath string) *Collector {
+	return &Collector{
+		ProcPath: procPath,
+		SysPath:  sysPath,
+		DiskPath: diskPath,
+	}
+}
+
+// Collect reads a new snapshot and remembers it as the latest
+func (c *Collector) Collect(now time.Time) (Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := Snapshot{Timestamp: now, Disks: []DiskIO{}, Network: []NetworkIO{}}
+
+	cpu, cores, err := c.readCPU()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.CPUCores = cores
+
+	if err := c.readMemory(&snapshot); err != nil {
+		return Snapshot{}, err
+	}
+	if err := c.readLoad(&snapshot); err != nil {
+		return Snapshot{}, err
+	}
+
+	current := &counters{timestamp: now, cpu: cpu}
+	current.disks, _ = c.readDisks()
+	current.network, _ = c.readNetwork()
+
+	previous := c.previous
+	if previous == nil {
+		previous = &counters{}
+	}
+	snapshot.CPUPercent = cpu.percentSince(previous.cpu)
+
+	if elapsed := now.Sub(previous.timestamp).Seconds(); previous.timestamp.IsZero() || elapsed <= 0 {
+		snapshot.Disks = zeroDisks(current.disks)
+		snapshot.Network = zeroNetwork(current.network)
+	} else {
+		snapshot.Disks = diskRates(previous.disks, current.disks, elapsed)
+		snapshot.Network = networkRates(previous.network, current.network, elapsed)
+	}
+
+	snapshot.Pressure = c.readPressure()
+	if c.DiskPath != "" {
+		snapshot.Filesystem = statFilesystem(c.DiskPath)
+	}
+
+	c.previous = current
+	c.latest = &snapshot
+
+	return snapshot, nil
+}
+
+// Latest returns the most recent snapshot, if any was collected
+func (c *Collector) Latest() (Snapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.latest == nil {
+		return Snapshot{}, false
+	}
+	return *c.latest, true
+}
+
+// Metric flattens a snapshot into the summary kept in history
+func (s Snapshot) Metric() storage.HostMetric {
+	metric := storage.HostMetric{
+		Timestamp:  s.Timestamp,
+		CPUPercent: s.CPUPercent,
+		MemUsed:    s.MemUsed,
+		MemTotal:   s.MemTotal,
+		MemPercent: s.MemPercent,
+		Load1:      s.Load1,
+	}
+	if s.Filesystem != nil {
+		metric.DiskUsed = s.Filesystem.Used
+	}
+	for _, disk := range s.Disks {
+		metric.DiskReadKBps += disk.ReadKBps
+		metric.DiskWriteKBps += disk.WriteKBps
+	}
+	for _, iface := range s.Network {
+		metric.NetRxKBps += iface.RxKBps
+		metric.NetTxKBps += iface.TxKBps
+	}
+	if s.Pressure != nil {
+		metric.CPUPressure = s.Pressure.CPU.SomeAvg10
+		metric.MemoryPressure = s.Pressure.Memory.SomeAvg10
+		metric.IOPressure = s.Pressure.IO.SomeAvg10
+	}
+	return metric
+}
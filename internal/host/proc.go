@@ -0,0 +1,326 @@
+package host
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sectorSize is the unit /proc/diskstats reports sectors in, regardless of the device
+const sectorSize = 512
+
+// cpuTimes holds cumulative jiffies from the aggregate cpu line of /proc/stat
+type cpuTimes struct {
+	total uint64
+	idle  uint64 // idle + iowait
+}
+
+// diskCounters holds cumulative counters of a block device from /proc/diskstats
+type diskCounters struct {
+	sectorsRead    uint64
+	sectorsWritten uint64
+	ioMillis       uint64
+}
+
+// netCounters holds cumulative byte counters of an interface from /proc/net/dev
+type netCounters struct {
+	rxBytes uint64
+	txBytes uint64
+}
+
+// percentSince returns the busy share of CPU time between two readings
+func (t cpuTimes) percentSince(previous cpuTimes) float64 {
+	total := float64(t.total - previous.total)
+	if total <= 0 {
+		return 0
+	}
+	idle := float64(t.idle - previous.idle)
+	return (total - idle) / total * 100.0
+}
+
+// readCPU reads aggregate CPU time and counts the cores listed in /proc/stat
+func (c *Collector) readCPU() (cpuTimes, int, error) {
+	file, err := os.Open(filepath.Join(c.ProcPath, "stat"))
+	if err != nil {
+		return cpuTimes{}, 0, err
+	}
+	defer file.Close()
+
+	var times cpuTimes
+	cores := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			cores++
+			continue
+		}
+
+		// user nice system idle iowait irq softirq steal; guest time is already in user
+		for i, field := range fields[1:] {
+			if i >= 8 {
+				break
+			}
+			value, _ := strconv.ParseUint(field, 10, 64)
+			times.total += value
+			if i == 3 || i == 4 {
+				times.idle += value
+			}
+		}
+	}
+
+	if times.total == 0 {
+		return cpuTimes{}, 0, fmt.Errorf("no cpu line in %s", file.Name())
+	}
+	return times, cores, scanner.Err()
+}
+
+// readMemory reads memory and swap usage from /proc/meminfo
+func (c *Collector) readMemory(snapshot *Snapshot) error {
+	values, err := readKeyValues(filepath.Join(c.ProcPath, "meminfo"))
+	if err != nil {
+		return err
+	}
+
+	// meminfo reports kB
+	snapshot.MemTotal = float64(values["MemTotal"]) / 1024
+	snapshot.MemAvailable = float64(values["MemAvailable"]) / 1024
+	snapshot.MemUsed = snapshot.MemTotal - snapshot.MemAvailable
+	if snapshot.MemTotal > 0 {
+		snapshot.MemPercent = snapshot.MemUsed / snapshot.MemTotal * 100.0
+	}
+	snapshot.SwapTotal = float64(values["SwapTotal"]) / 1024
+	snapshot.SwapUsed = snapshot.SwapTotal - float64(values["SwapFree"])/1024
+
+	return nil
+}
+
+// readLoad reads the load averages from /proc/loadavg
+func (c *Collector) readLoad(snapshot *Snapshot) error {
+	data, err := os.ReadFile(filepath.Join(c.ProcPath, "loadavg"))
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return fmt.Errorf("unexpected loadavg format %q", string(data))
+	}
+	snapshot.Load1, _ = strconv.ParseFloat(fields[0], 64)
+	snapshot.Load5, _ = strconv.ParseFloat(fields[1], 64)
+	snapshot.Load15, _ = strconv.ParseFloat(fields[2], 64)
+
+	return nil
+}
+
+// readDisks reads whole-disk counters from /proc/diskstats. Partitions, loop and
+// ram devices are skipped by keeping only devices listed in /sys/block that are
+// backed by real hardware.
+func (c *Collector) readDisks() (map[string]diskCounters, error) {
+	file, err := os.Open(filepath.Join(c.ProcPath, "diskstats"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]diskCounters)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			continue
+		}
+
+		device := fields[2]
+		if !c.isPhysicalDisk(device) {
+			continue
+		}
+
+		read, _ := strconv.ParseUint(fields[5], 10, 64)
+		written, _ := strconv.ParseUint(fields[9], 10, 64)
+		ioMillis, _ := strconv.ParseUint(fields[12], 10, 64)
+		result[device] = diskCounters{sectorsRead: read, sectorsWritten: written, ioMillis: ioMillis}
+	}
+
+	return result, scanner.Err()
+}
+
+// readNetwork reads byte counters of physical interfaces from /proc/net/dev.
+// Loopback, bridges and veth pairs live under /sys/devices/virtual/net and are skipped.
+func (c *Collector) readNetwork() (map[string]netCounters, error) {
+	file, err := os.Open(filepath.Join(c.ProcPath, "net", "dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]netCounters)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if c.isVirtualInterface(name) {
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) < 9 {
+			continue
+		}
+		rx, _ := strconv.ParseUint(fields[0], 10, 64)
+		tx, _ := strconv.ParseUint(fields[8], 10, 64)
+		result[name] = netCounters{rxBytes: rx, txBytes: tx}
+	}
+
+	return result, scanner.Err()
+}
+
+// readPressure reads /proc/pressure/{cpu,memory,io}, returning nil when PSI is unavailable
+func (c *Collector) readPressure() *Pressure {
+	cpu, err := readPressureFile(filepath.Join(c.ProcPath, "pressure", "cpu"))
+	if err != nil {
+		return nil
+	}
+	memory, _ := readPressureFile(filepath.Join(c.ProcPath, "pressure", "memory"))
+	io, _ := readPressureFile(filepath.Join(c.ProcPath, "pressure", "io"))
+
+	return &Pressure{CPU: cpu, Memory: memory, IO: io}
+}
+
+// PressureAvailable reports whether the kernel exposes pressure stall information
+// (Linux 4.20+ built with CONFIG_PSI, and not disabled with psi=0)
+func (c *Collector) PressureAvailable() bool {
+	return c.readPressure() != nil
+}
+
+// readPressureFile parses lines such as "some avg10=1.23 avg60=0.50 avg300=0.10 total=12345"
+func readPressureFile(path string) (PressureStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PressureStats{}, err
+	}
+
+	var stats PressureStats
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		values := make(map[string]float64)
+		for _, field := range fields[1:] {
+			if key, value, found := strings.Cut(field, "="); found {
+				values[key], _ = strconv.ParseFloat(value, 64)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			stats.SomeAvg10, stats.SomeAvg60, stats.SomeAvg300 = values["avg10"], values["avg60"], values["avg300"]
+		case "full":
+			stats.FullAvg10, stats.FullAvg60, stats.FullAvg300 = values["avg10"], values["avg60"], values["avg300"]
+		}
+	}
+
+	return stats, nil
+}
+
+// isPhysicalDisk reports whether a block device is a whole, non-virtual disk
+func (c *Collector) isPhysicalDisk(device string) bool {
+	if _, err := os.Stat(filepath.Join(c.SysPath, "block", device)); err != nil {
+		return false
+	}
+	return !strings.HasPrefix(device, "loop") && !strings.HasPrefix(device, "ram") && !strings.HasPrefix(device, "zram")
+}
+
+// isVirtualInterface reports whether a network interface is loopback, a bridge or a veth
+func (c *Collector) isVirtualInterface(name string) bool {
+	_, err := os.Stat(filepath.Join(c.SysPath, "devices", "virtual", "net", name))
+	return err == nil || name == "lo"
+}
+
+// readKeyValues parses "Key: value kB" files such as /proc/meminfo
+func readKeyValues(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, rest, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err == nil {
+			result[key] = value
+		}
+	}
+
+	return result, scanner.Err()
+}
+
+// diskRates converts counter deltas into per-second throughput
+func diskRates(previous, current map[string]diskCounters, elapsed float64) []DiskIO {
+	result := []DiskIO{}
+	for device, now := range current {
+		before, exists := previous[device]
+		if !exists {
+			before = now
+		}
+		result = append(result, DiskIO{
+			Device:      device,
+			ReadKBps:    float64(now.sectorsRead-before.sectorsRead) * sectorSize / 1024 / elapsed,
+			WriteKBps:   float64(now.sectorsWritten-before.sectorsWritten) * sectorSize / 1024 / elapsed,
+			Utilization: min(float64(now.ioMillis-before.ioMillis)/(elapsed*1000)*100.0, 100),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Device < result[j].Device })
+	return result
+}
+
+// networkRates converts counter deltas into per-second throughput
+func networkRates(previous, current map[string]netCounters, elapsed float64) []NetworkIO {
+	result := []NetworkIO{}
+	for name, now := range current {
+		before, exists := previous[name]
+		if !exists {
+			before = now
+		}
+		result = append(result, NetworkIO{
+			Interface: name,
+			RxKBps:    float64(now.rxBytes-before.rxBytes) / 1024 / elapsed,
+			TxKBps:    float64(now.txBytes-before.txBytes) / 1024 / elapsed,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Interface < result[j].Interface })
+	return result
+}
+
+// zeroDisks lists devices with zero throughput for the first sample
+func zeroDisks(current map[string]diskCounters) []DiskIO {
+	return diskRates(current, current, 1)
+}
+
+// zeroNetwork lists interfaces with zero throughput for the first sample
+func zeroNetwork(current map[string]netCounters) []NetworkIO {
+	return networkRates(current, current, 1)
+}
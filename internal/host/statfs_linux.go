@@ -0,0 +1,27 @@
+//go:build linux
+
+package host
+
+import (
+	"syscall"
+)
+
+// statFilesystem reports the usage of the filesystem containing path
+func statFilesystem(path string) *Filesystem {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil
+	}
+
+	blockSize := float64(stat.Bsize)
+	total := float64(stat.Blocks) * blockSize / 1024 / 1024
+	available := float64(stat.Bavail) * blockSize / 1024 / 1024
+	used := total - float64(stat.Bfree)*blockSize/1024/1024
+
+	fs := &Filesystem{Path: path, Total: total, Used: used, Available: available}
+	// Match df, which excludes blocks reserved for root from the total
+	if used+available > 0 {
+		fs.UsedPercent = used / (used + available) * 100.0
+	}
+	return fs
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package host
+
+// statFilesystem is only implemented on Linux, like the rest of the collector
+func statFilesystem(path string) *Filesystem {
+	return nil
+}
@@ -0,0 +1,77 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/storage"
+)
+
+// SummarizeDiskUsage totals a disk usage report per object kind, computing
+// reclaimable space the same way `docker system df` does
+func SummarizeDiskUsage(usage types.DiskUsage, timestamp time.Time) storage.DiskUsageSnapshot {
+	snapshot := storage.DiskUsageSnapshot{Timestamp: timestamp}
+
+	// Layers shared with images in use cannot be reclaimed
+	var usedImageSize int64
+	for _, img := range usage.Images {
+		snapshot.Images.Count++
+		if img.Containers > 0 {
+			snapshot.Images.Active++
+			if img.SharedSize >= 0 {
+				usedImageSize += img.Size - img.SharedSize
+			}
+		}
+	}
+	snapshot.Images.Size = usage.LayersSize
+	snapshot.Images.Reclaimable = max(usage.LayersSize-usedImageSize, 0)
+
+	for _, c := range usage.Containers {
+		snapshot.Containers.Count++
+		snapshot.Containers.Size += c.SizeRw
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" {
+			snapshot.Containers.Active++
+		} else {
+			snapshot.Containers.Reclaimable += c.SizeRw
+		}
+	}
+
+	for _, v := range usage.Volumes {
+		snapshot.Volumes.Count++
+		if v.UsageData == nil {
+			continue
+		}
+		if v.UsageData.RefCount > 0 {
+			snapshot.Volumes.Active++
+		}
+		if v.UsageData.Size < 0 {
+			continue
+		}
+		snapshot.Volumes.Size += v.UsageData.Size
+		if v.UsageData.RefCount == 0 {
+			snapshot.Volumes.Reclaimable += v.UsageData.Size
+		}
+	}
+
+	for _, record := range usage.BuildCache {
+		snapshot.BuildCache.Count++
+		if record.InUse {
+			snapshot.BuildCache.Active++
+		}
+		// Shared records are counted by the images using them
+		if record.Shared {
+			continue
+		}
+		snapshot.BuildCache.Size += record.Size
+		if !record.InUse {
+			snapshot.BuildCache.Reclaimable += record.Size
+		}
+	}
+
+	snapshot.TotalSize = snapshot.Images.Size + snapshot.Containers.Size + snapshot.Volumes.Size + snapshot.BuildCache.Size
+	snapshot.TotalReclaimable = snapshot.Images.Reclaimable + snapshot.Containers.Reclaimable +
+		snapshot.Volumes.Reclaimable + snapshot.BuildCache.Reclaimable
+
+	return snapshot
+}
@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+)
+
+// ImageInfo describes a local image and the containers using it
+type ImageInfo struct {
+	ID         string            `json:"id"`
+	Tags       []string          `json:"tags"`
+	Digests    []string          `json:"digests"`
+	Size       int64             `json:"size"`        // bytes
+	SharedSize int64             `json:"shared_size"` // bytes shared with other images, -1 if unknown
+	Created    time.Time         `json:"created"`
+	Dangling   bool              `json:"dangling"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Containers []ContainerRef    `json:"containers"`
+}
+
+// ContainerRef identifies a container using a resource
+type ContainerRef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// OutdatedContainer is a container running an image that is no longer the local tag's image
+type OutdatedContainer struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Image          string `json:"image"`
+	RunningImageID string `json:"running_image_id"`
+	CurrentImageID string `json:"current_image_id"`
+}
+
+// LayerInfo describes one layer of an image's history
+type LayerInfo struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"created_by"`
+	Created   time.Time `json:"created"`
+	Size      int64     `json:"size"` // bytes
+	Tags      []string  `json:"tags,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	Oversized bool      `json:"oversized"`
+}
+
+// BuildImageInventory lists images with the containers that use them, largest first
+func BuildImageInventory(images []types.ImageSummary, containers []types.Container) []ImageInfo {
+	users := make(map[string][]ContainerRef)
+	for _, c := range containers {
+		users[c.ImageID] = append(users[c.ImageID], containerRef(c))
+	}
+
+	result := make([]ImageInfo, 0, len(images))
+	for _, img := range images {
+		tags := realTags(img.RepoTags)
+		info := ImageInfo{
+			ID:         img.ID,
+			Tags:       tags,
+			Digests:    realDigests(img.RepoDigests),
+			Size:       img.Size,
+			SharedSize: img.SharedSize,
+			Created:    time.Unix(img.Created, 0),
+			Dangling:   len(tags) == 0,
+			Labels:     img.Labels,
+			Containers: users[img.ID],
+		}
+		if info.Containers == nil {
+			info.Containers = []ContainerRef{}
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Size > result[j].Size
+	})
+
+	return result
+}
+
+// FindOutdatedContainers returns containers whose image ID differs from the image the
+// same reference resolves to locally, which happens after pulling a newer image.
+// Once the tag moves, Docker lists such containers by image ID; references holds the
+// reference they were created from (Config.Image), keyed by container ID.
+func FindOutdatedContainers(images []types.ImageSummary, containers []types.Container, references map[string]string) []OutdatedContainer {
+	byTag := make(map[string]string)
+	for _, img := range images {
+		for _, tag := range realTags(img.RepoTags) {
+			byTag[tag] = img.ID
+		}
+	}
+
+	result := []OutdatedContainer{}
+	for _, c := range containers {
+		reference := c.Image
+		if strings.HasPrefix(reference, "sha256:") {
+			reference = references[c.ID]
+		}
+		// Containers created from an image ID rather than a tag cannot go stale
+		if reference == "" || strings.HasPrefix(reference, "sha256:") {
+			continue
+		}
+
+		currentID, exists := byTag[NormalizeTag(reference)]
+		if !exists || currentID == c.ImageID {
+			continue
+		}
+
+		ref := containerRef(c)
+		result = append(result, OutdatedContainer{
+			ID:             ref.ID,
+			Name:           ref.Name,
+			Image:          reference,
+			RunningImageID: c.ImageID,
+			CurrentImageID: currentID,
+		})
+	}
+
+	return result
+}
+
+// BuildLayers converts image history into layers, newest first, flagging layers
+// at or above the oversized threshold
+func BuildLayers(history []image.HistoryResponseItem, oversizedBytes int64) []LayerInfo {
+	result := make([]LayerInfo, 0, len(history))
+	for _, item := range history {
+		id := item.ID
+		if id == "<missing>" {
+			id = ""
+		}
+		result = append(result, LayerInfo{
+			ID:        id,
+			CreatedBy: item.CreatedBy,
+			Created:   time.Unix(item.Created, 0),
+			Size:      item.Size,
+			Tags:      item.Tags,
+			Comment:   item.Comment,
+			Oversized: oversizedBytes > 0 && item.Size >= oversizedBytes,
+		})
+	}
+	return result
+}
+
+// NormalizeTag adds the implicit ":latest" tag to an image reference without one
+func NormalizeTag(reference string) string {
+	if strings.Contains(reference, "@") {
+		return reference
+	}
+	lastSlash := strings.LastIndex(reference, "/")
+	if !strings.Contains(reference[lastSlash+1:], ":") {
+		return reference + ":latest"
+	}
+	return reference
+}
+
+// realTags drops the placeholder tags Docker reports for untagged images
+func realTags(tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if tag != "<none>:<none>" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// realDigests drops the placeholder digests Docker reports for unpushed images
+func realDigests(digests []string) []string {
+	result := []string{}
+	for _, digest := range digests {
+		if digest != "<none>@<none>" {
+			result = append(result, digest)
+		}
+	}
+	return result
+}
+
+// containerRef builds a reference to a container from its list entry
+func containerRef(c types.Container) ContainerRef {
+	name := "unknown"
+	if len(c.Names) > 0 {
+		name = c.Names[0][1:] // Remove leading slash
+	}
+	return ContainerRef{ID: c.ID[:12], Name: name, State: c.State}
+}
@@ -0,0 +1,94 @@
+package inventory
+
+import (
+	"sort"
+
+	"github.com/docker/docker/api/types"
+)
+
+// NetworkInfo describes a network and the containers attached to it
+type NetworkInfo struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Driver     string            `json:"driver"`
+	Scope      string            `json:"scope"`
+	Internal   bool              `json:"internal"`
+	EnableIPv6 bool              `json:"enable_ipv6"`
+	Subnets    []Subnet          `json:"subnets"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Containers []NetworkEndpoint `json:"containers"`
+}
+
+// Subnet is an IPAM pool of a network
+type Subnet struct {
+	Subnet  string `json:"subnet"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// NetworkEndpoint is a container attached to a network
+type NetworkEndpoint struct {
+	ContainerRef
+	IPv4Address string `json:"ipv4_address,omitempty"`
+	IPv6Address string `json:"ipv6_address,omitempty"`
+	MacAddress  string `json:"mac_address,omitempty"`
+}
+
+// BuildNetworkInventory lists networks with their subnets and attached containers.
+// Attachments come from the container list, since the network list does not include them.
+func BuildNetworkInventory(networks []types.NetworkResource, containers []types.Container) []NetworkInfo {
+	endpoints := make(map[string][]NetworkEndpoint)
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for _, settings := range c.NetworkSettings.Networks {
+			if settings == nil {
+				continue
+			}
+			endpoints[settings.NetworkID] = append(endpoints[settings.NetworkID], NetworkEndpoint{
+				ContainerRef: containerRef(c),
+				IPv4Address:  settings.IPAddress,
+				IPv6Address:  settings.GlobalIPv6Address,
+				MacAddress:   settings.MacAddress,
+			})
+		}
+	}
+
+	result := make([]NetworkInfo, 0, len(networks))
+	for _, n := range networks {
+		subnets := []Subnet{}
+		for _, pool := range n.IPAM.Config {
+			subnets = append(subnets, Subnet{Subnet: pool.Subnet, Gateway: pool.Gateway})
+		}
+
+		info := NetworkInfo{
+			ID:         shortID(n.ID),
+			Name:       n.Name,
+			Driver:     n.Driver,
+			Scope:      n.Scope,
+			Internal:   n.Internal,
+			EnableIPv6: n.EnableIPv6,
+			Subnets:    subnets,
+			Labels:     n.Labels,
+			Containers: endpoints[n.ID],
+		}
+		if info.Containers == nil {
+			info.Containers = []NetworkEndpoint{}
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// shortID truncates an ID to the 12 characters Docker displays
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,216 @@
+package inventory
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+
+	"gocontainerops/internal/container"
+)
+
+// AnonymousVolumeLabel marks volumes Docker created without a name
+const AnonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// predefinedNetworks cannot be removed
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// PruneOptions selects which unused objects a prune removes
+type PruneOptions struct {
+	// Until only removes objects created (or, for build cache, last used) before this time
+	Until time.Time
+	// Selector only removes objects whose labels match
+	Selector container.Selector
+	// ProtectedLabel keeps any object carrying this label with a true value
+	ProtectedLabel string
+	// All also removes unused tagged images, named volumes and shared build cache
+	All bool
+}
+
+// PruneItem is an object a prune removes or keeps
+type PruneItem struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Size    int64     `json:"size"` // bytes freed by removing it
+	Created time.Time `json:"created,omitempty"`
+	Tags    []string  `json:"tags,omitempty"` // every tag of an image, each removed in turn
+}
+
+// PrunePlan lists what a prune would remove, and what it keeps because it is protected
+type PrunePlan struct {
+	Kind      string      `json:"kind"`
+	Remove    []PruneItem `json:"remove"`
+	Protected []PruneItem `json:"protected"`
+	Bytes     int64       `json:"bytes"`
+}
+
+// PlanContainerPrune selects stopped containers
+func PlanContainerPrune(containers []types.Container, opts PruneOptions) PrunePlan {
+	plan := newPlan("containers")
+	for _, c := range containers {
+		if c.State != "exited" && c.State != "created" && c.State != "dead" {
+			continue
+		}
+		ref := containerRef(c)
+		item := PruneItem{ID: ref.ID, Name: ref.Name, Size: c.SizeRw, Created: time.Unix(c.Created, 0)}
+		plan.consider(item, c.Labels, opts)
+	}
+	return plan.sorted()
+}
+
+// PlanImagePrune selects dangling images, or every image no container uses when opts.All is set
+func PlanImagePrune(images []types.ImageSummary, containers []types.Container, opts PruneOptions) PrunePlan {
+	used := make(map[string]bool)
+	for _, c := range containers {
+		used[c.ImageID] = true
+	}
+
+	plan := newPlan("images")
+	for _, img := range images {
+		tags := realTags(img.RepoTags)
+		if used[img.ID] || (len(tags) > 0 && !opts.All) {
+			continue
+		}
+
+		// Layers shared with other images stay on disk
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+
+		name := ""
+		if len(tags) > 0 {
+			name = tags[0]
+		}
+		item := PruneItem{ID: img.ID, Name: name, Size: size, Created: time.Unix(img.Created, 0), Tags: tags}
+		plan.consider(item, img.Labels, opts)
+	}
+	return plan.sorted()
+}
+
+// PlanVolumePrune selects volumes no container mounts. Only anonymous volumes are
+// selected unless opts.All is set, matching `docker volume prune`.
+func PlanVolumePrune(volumes []*volume.Volume, containers []types.Container, opts PruneOptions) PrunePlan {
+	mounted := make(map[string]bool)
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Type == "volume" {
+				mounted[m.Name] = true
+			}
+		}
+	}
+
+	plan := newPlan("volumes")
+	for _, v := range volumes {
+		if mounted[v.Name] || (v.UsageData != nil && v.UsageData.RefCount > 0) {
+			continue
+		}
+		if _, anonymous := v.Labels[AnonymousVolumeLabel]; !anonymous && !opts.All {
+			continue
+		}
+
+		item := PruneItem{ID: v.Name, Name: v.Name}
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			item.Size = v.UsageData.Size
+		}
+		if created, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil {
+			item.Created = created
+		}
+		plan.consider(item, v.Labels, opts)
+	}
+	return plan.sorted()
+}
+
+// PlanNetworkPrune selects custom networks no container is attached to
+func PlanNetworkPrune(networks []types.NetworkResource, containers []types.Container, opts PruneOptions) PrunePlan {
+	attached := make(map[string]bool)
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for _, settings := range c.NetworkSettings.Networks {
+			if settings != nil {
+				attached[settings.NetworkID] = true
+			}
+		}
+	}
+
+	plan := newPlan("networks")
+	for _, n := range networks {
+		if predefinedNetworks[n.Name] || n.Ingress || attached[n.ID] || len(n.Containers) > 0 {
+			continue
+		}
+		item := PruneItem{ID: shortID(n.ID), Name: n.Name, Created: n.Created}
+		plan.consider(item, n.Labels, opts)
+	}
+	return plan.sorted()
+}
+
+// PlanBuildCachePrune selects build cache records not in use. Shared records are
+// only selected when opts.All is set. Build cache has no labels, so the selector
+// and protected label do not apply.
+func PlanBuildCachePrune(records []*types.BuildCache, opts PruneOptions) PrunePlan {
+	plan := newPlan("buildcache")
+	for _, record := range records {
+		if record.InUse || (record.Shared && !opts.All) {
+			continue
+		}
+
+		lastUsed := record.CreatedAt
+		if record.LastUsedAt != nil {
+			lastUsed = *record.LastUsedAt
+		}
+		if !opts.Until.IsZero() && !lastUsed.Before(opts.Until) {
+			continue
+		}
+
+		plan.Remove = append(plan.Remove, PruneItem{
+			ID:      record.ID,
+			Name:    record.Description,
+			Size:    record.Size,
+			Created: record.CreatedAt,
+		})
+		plan.Bytes += record.Size
+	}
+	return plan.sorted()
+}
+
+// IsProtected reports whether labels carry the protected label with a true value
+func IsProtected(labels map[string]string, protectedLabel string) bool {
+	if protectedLabel == "" {
+		return false
+	}
+	protected, _ := strconv.ParseBool(labels[protectedLabel])
+	return protected
+}
+
+// newPlan creates an empty plan for a kind of object
+func newPlan(kind string) PrunePlan {
+	return PrunePlan{Kind: kind, Remove: []PruneItem{}, Protected: []PruneItem{}}
+}
+
+// consider applies the age, selector and protection rules to a candidate
+func (p *PrunePlan) consider(item PruneItem, labels map[string]string, opts PruneOptions) {
+	if !opts.Until.IsZero() && !item.Created.Before(opts.Until) {
+		return
+	}
+	if !opts.Selector.Matches(labels) {
+		return
+	}
+	if IsProtected(labels, opts.ProtectedLabel) {
+		p.Protected = append(p.Protected, item)
+		return
+	}
+	p.Remove = append(p.Remove, item)
+	p.Bytes += item.Size
+}
+
+// sorted orders the plan largest first so the biggest wins are listed at the top
+func (p PrunePlan) sorted() PrunePlan {
+	sort.SliceStable(p.Remove, func(i, j int) bool {
+		return p.Remove[i].Size > p.Remove[j].Size
+	})
+	return p
+}
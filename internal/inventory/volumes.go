@@ -0,0 +1,83 @@
+package inventory
+
+import (
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// VolumeInfo describes a volume and the containers mounting it
+type VolumeInfo struct {
+	Name       string            `json:"name"`
+	Driver     string            `json:"driver"`
+	Mountpoint string            `json:"mountpoint"`
+	Scope      string            `json:"scope"`
+	CreatedAt  string            `json:"created_at,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Size       int64             `json:"size"` // bytes, -1 if unknown
+	Containers []VolumeMount     `json:"containers"`
+}
+
+// VolumeMount is a container mounting a volume
+type VolumeMount struct {
+	ContainerRef
+	Destination string `json:"destination"`
+	ReadWrite   bool   `json:"read_write"`
+}
+
+// BuildVolumeInventory lists volumes with their size and the containers mounting them.
+// Sizes come from the disk usage report, since the volume list does not include them.
+func BuildVolumeInventory(volumes []*volume.Volume, usage []*volume.Volume, containers []types.Container) []VolumeInfo {
+	sizes := make(map[string]int64)
+	for _, v := range usage {
+		if v.UsageData != nil {
+			sizes[v.Name] = v.UsageData.Size
+		}
+	}
+
+	mounts := make(map[string][]VolumeMount)
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Type != "volume" {
+				continue
+			}
+			mounts[m.Name] = append(mounts[m.Name], VolumeMount{
+				ContainerRef: containerRef(c),
+				Destination:  m.Destination,
+				ReadWrite:    m.RW,
+			})
+		}
+	}
+
+	result := make([]VolumeInfo, 0, len(volumes))
+	for _, v := range volumes {
+		size, known := sizes[v.Name]
+		if !known {
+			size = -1
+		}
+		info := VolumeInfo{
+			Name:       v.Name,
+			Driver:     v.Driver,
+			Mountpoint: v.Mountpoint,
+			Scope:      v.Scope,
+			CreatedAt:  v.CreatedAt,
+			Labels:     v.Labels,
+			Size:       size,
+			Containers: mounts[v.Name],
+		}
+		if info.Containers == nil {
+			info.Containers = []VolumeMount{}
+		}
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/storage"
+)
+
+// buildConfigSnapshot captures the deployment-relevant configuration of a container
+func buildConfigSnapshot(info types.ContainerJSON, timestamp time.Time) storage.ConfigSnapshot {
+	view := container.BuildInspectView(info)
+
+	snapshot := storage.ConfigSnapshot{
+		Key:           container.ConfigKey(view.Labels, view.Name),
+		ContainerID:   view.ID,
+		ContainerName: view.Name,
+		Timestamp:     timestamp,
+		Image:         view.Image,
+		ImageID:       view.ImageID,
+		Command:       view.Command,
+		Entrypoint:    view.Entrypoint,
+		Env:           view.Env,
+		Mounts:        make([]string, 0, len(view.Mounts)),
+		Labels:        make(map[string]string, len(view.Labels)),
+		Limits: map[string]string{
+			"memory":             strconv.FormatInt(view.Resources.Memory, 10),
+			"memory_reservation": strconv.FormatInt(view.Resources.MemoryReservation, 10),
+			"memory_swap":        strconv.FormatInt(view.Resources.MemorySwap, 10),
+			"nano_cpus":          strconv.FormatInt(view.Resources.NanoCPUs, 10),
+			"cpu_shares":         strconv.FormatInt(view.Resources.CPUShares, 10),
+			"cpu_quota":          strconv.FormatInt(view.Resources.CPUQuota, 10),
+			"cpu_period":         strconv.FormatInt(view.Resources.CPUPeriod, 10),
+			"cpuset_cpus":        view.Resources.CpusetCpus,
+			"pids_limit":         strconv.FormatInt(view.Resources.PidsLimit, 10),
+			"restart_policy":     view.RestartPolicy.Name,
+		},
+	}
+
+	for _, m := range view.Mounts {
+		mode := "ro"
+		if m.ReadWrite {
+			mode = "rw"
+		}
+		snapshot.Mounts = append(snapshot.Mounts, fmt.Sprintf("%s:%s:%s", m.Source, m.Destination, mode))
+	}
+
+	// The container number differs between replicas of the same service
+	for k, v := range view.Labels {
+		if k != container.ComposeNumberLabel {
+			snapshot.Labels[k] = v
+		}
+	}
+
+	return snapshot
+}
@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/docker"
+	"gocontainerops/internal/host"
+	"gocontainerops/internal/storage"
+)
+
+// ContentionTracker periodically combines host pressure stall information with
+// per-container CPU, throttling and block I/O rates from the stats manager.
+// Every contention episode is recorded as an event in the history store when it ends.
+type ContentionTracker struct {
+	DockerService docker.DockerService
+	HistoryStore  storage.HistoryStore
+	Host          *host.Collector
+	Stats         *StatsManager
+	Detector      *container.ContentionDetector
+	Interval      time.Duration
+
+	previous map[string]contentionCounters
+}
+
+// contentionCounters holds the cumulative counters of the previous sample
+type contentionCounters struct {
+	timestamp        time.Time
+	blockKB          float64
+	periods          uint64
+	throttledPeriods uint64
+}
+
+// NewContentionTracker creates a new contention tracker
+func NewContentionTracker(ds docker.DockerService, historyStore storage.HistoryStore, hostCollector *host.Collector, stats *StatsManager, detector *container.ContentionDetector, interval time.Duration) *ContentionTracker {
+	return &ContentionTracker{
+		DockerService: ds,
+		HistoryStore:  historyStore,
+		Host:          hostCollector,
+		Stats:         stats,
+		Detector:      detector,
+		Interval:      interval,
+		previous:      make(map[string]contentionCounters),
+	}
+}
+
+// Run samples immediately and then on every interval until the context is cancelled
+func (t *ContentionTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Sample(ctx, time.Now()); err != nil {
+			log.Printf("Error sampling resource contention: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample feeds the current pressure and container usage into the detector. The
+// tracker is only started on hosts with PSI; snapshots without it are skipped.
+func (t *ContentionTracker) Sample(ctx context.Context, now time.Time) error {
+	snapshot, ok := t.Host.Latest()
+	if !ok || snapshot.Pressure == nil {
+		return nil
+	}
+
+	containers, err := t.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]contentionCounters)
+	var samples []container.ContentionSample
+	for _, c := range containers {
+		stats, ok := t.Stats.Latest(c.ID)
+		if !ok {
+			continue
+		}
+
+		data := container.ProcessStats(c, stats, 0)
+		throttling := stats.CPUStats.ThrottlingData
+		counters := contentionCounters{
+			timestamp:        stats.Read,
+			blockKB:          data.BlockInput + data.BlockOutput,
+			periods:          throttling.Periods,
+			throttledPeriods: throttling.ThrottledPeriods,
+		}
+		current[c.ID] = counters
+
+		// Rates need two samples; counters going backwards mean a restart
+		previous, seen := t.previous[c.ID]
+		elapsed := counters.timestamp.Sub(previous.timestamp).Seconds()
+		if !seen || elapsed <= 0 || counters.blockKB < previous.blockKB || counters.periods < previous.periods {
+			continue
+		}
+
+		sample := container.ContentionSample{
+			ID:         data.ID,
+			Name:       data.Name,
+			CPUPercent: data.CPUPercent,
+			IOKBps:     (counters.blockKB - previous.blockKB) / elapsed,
+		}
+		if periods := counters.periods - previous.periods; periods > 0 {
+			sample.ThrottledRatio = float64(counters.throttledPeriods-previous.throttledPeriods) / float64(periods)
+		}
+		samples = append(samples, sample)
+	}
+	t.previous = current
+
+	pressure := map[string]float64{
+		container.ContentionCPU: snapshot.Pressure.CPU.SomeAvg10,
+		container.ContentionIO:  snapshot.Pressure.IO.SomeAvg10,
+	}
+	for _, episode := range t.Detector.Observe(pressure, samples, now) {
+		if err := t.HistoryStore.AddEvent(contentionEvent(episode)); err != nil {
+			log.Printf("Error recording contention event: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// contentionEvent builds a history event describing an ended contention episode
+func contentionEvent(episode container.ContentionEpisode) storage.ContainerEvent {
+	contributors := make([]string, 0, len(episode.Contributors))
+	for _, c := range episode.Contributors {
+		contributors = append(contributors, fmt.Sprintf("%s:%.2f", c.Name, c.Share))
+	}
+	victims := make([]string, 0, len(episode.Victims))
+	for _, v := range episode.Victims {
+		victims = append(victims, fmt.Sprintf("%s:%s", v.Name, v.Reason))
+	}
+
+	return storage.ContainerEvent{
+		EventType: "contention",
+		Timestamp: *episode.End,
+		Details: map[string]string{
+			"resource":         episode.Resource,
+			"start":            episode.Start.Format(time.RFC3339),
+			"duration_seconds": strconv.FormatFloat(episode.DurationSeconds, 'f', 0, 64),
+			"peak_pressure":    strconv.FormatFloat(episode.PeakPressure, 'f', 2, 64),
+			"contributors":     strings.Join(contributors, ","),
+			"victims":          strings.Join(victims, ","),
+		},
+	}
+}
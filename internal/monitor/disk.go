@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/docker"
+	"gocontainerops/internal/inventory"
+	"gocontainerops/internal/storage"
+)
+
+// DiskUsageTracker periodically records Docker's disk usage so growth can be charted
+type DiskUsageTracker struct {
+	DockerService docker.DockerService
+	HistoryStore  storage.HistoryStore
+	Interval      time.Duration
+}
+
+// NewDiskUsageTracker creates a new disk usage tracker
+func NewDiskUsageTracker(dockerService docker.DockerService, historyStore storage.HistoryStore, interval time.Duration) *DiskUsageTracker {
+	return &DiskUsageTracker{
+		DockerService: dockerService,
+		HistoryStore:  historyStore,
+		Interval:      interval,
+	}
+}
+
+// Run records a snapshot immediately and then on every interval until the context is cancelled
+func (t *DiskUsageTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := t.Record(ctx); err != nil {
+			log.Printf("Error recording disk usage: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Record takes a disk usage snapshot and stores it
+func (t *DiskUsageTracker) Record(ctx context.Context) (storage.DiskUsageSnapshot, error) {
+	usage, err := t.DockerService.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return storage.DiskUsageSnapshot{}, err
+	}
+
+	snapshot := inventory.SummarizeDiskUsage(usage, time.Now())
+	if err := t.HistoryStore.AddDiskUsage(snapshot); err != nil {
+		return storage.DiskUsageSnapshot{}, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,237 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/docker"
+	"gocontainerops/internal/host"
+	"gocontainerops/internal/storage"
+)
+
+// History used to fit the trends. Memory is fitted on the sampler's rollups over
+// the last hours; disk usage is sampled far less often and grows over days.
+const (
+	memoryForecastWindow = 6 * time.Hour
+	diskForecastWindow   = 7 * 24 * time.Hour
+)
+
+// diskForecastKey tracks the alert state of the data-root forecast
+const diskForecastKey = "disk"
+
+// ForecastTracker periodically forecasts when containers will reach their memory
+// limit and when Docker's disk usage will fill the data-root filesystem. Forecasts
+// entering or leaving the alert horizon are recorded as events in the history store.
+type ForecastTracker struct {
+	DockerService docker.DockerService
+	HistoryStore  storage.HistoryStore
+	Host          *host.Collector
+	Interval      time.Duration
+	Horizon       time.Duration
+
+	mu        sync.RWMutex
+	forecasts []container.Forecast
+	updated   time.Time
+	alerting  map[string]bool
+}
+
+// NewForecastTracker creates a new forecast tracker. Disk forecasts need the host
+// collector for the filesystem's capacity and are skipped without it.
+func NewForecastTracker(ds docker.DockerService, historyStore storage.HistoryStore, hostCollector *host.Collector, interval, horizon time.Duration) *ForecastTracker {
+	return &ForecastTracker{
+		DockerService: ds,
+		HistoryStore:  historyStore,
+		Host:          hostCollector,
+		Interval:      interval,
+		Horizon:       horizon,
+		alerting:      make(map[string]bool),
+	}
+}
+
+// Run forecasts immediately and then on every interval until the context is cancelled
+func (t *ForecastTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Forecast(ctx, time.Now()); err != nil {
+			log.Printf("Error forecasting resource exhaustion: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Forecast recomputes every forecast from the history store
+func (t *ForecastTracker) Forecast(ctx context.Context, now time.Time) error {
+	containers, err := t.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var forecasts []container.Forecast
+	for _, c := range containers {
+		id := c.ID[:12]
+		metrics, err := t.HistoryStore.GetMetricRollups(id, now.Add(-memoryForecastWindow), now)
+		if err != nil {
+			return err
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+
+		// A large drop means the container restarted or was OOM-killed; only the
+		// usage since then says anything about the current trend
+		start := 0
+		for i := 1; i < len(metrics); i++ {
+			if metrics[i].MemPeak < metrics[i-1].MemPeak/2 {
+				start = i
+			}
+		}
+		metrics = metrics[start:]
+
+		times := make([]time.Time, len(metrics))
+		values := make([]float64, len(metrics))
+		for i, metric := range metrics {
+			times[i] = metric.Timestamp
+			values[i] = metric.MemPeak // the limit is hit by peaks, not the mean
+		}
+
+		forecast := container.ForecastExhaustion(times, values, metrics[len(metrics)-1].MemLimit, now, t.Horizon)
+		forecast.Kind = "memory"
+		forecast.ID = id
+		if len(c.Names) > 0 {
+			forecast.Name = c.Names[0][1:] // Remove leading slash
+		}
+		forecasts = append(forecasts, forecast)
+	}
+
+	if disk, ok := t.forecastDisk(now); ok {
+		forecasts = append(forecasts, disk)
+	}
+
+	t.recordAlerts(forecasts, now)
+
+	t.mu.Lock()
+	t.forecasts = forecasts
+	t.updated = now
+	t.mu.Unlock()
+
+	return nil
+}
+
+// forecastDisk fits Docker's total disk usage and predicts when it takes up the
+// space still available on the data-root filesystem
+func (t *ForecastTracker) forecastDisk(now time.Time) (container.Forecast, bool) {
+	if t.Host == nil {
+		return container.Forecast{}, false
+	}
+	snapshot, ok := t.Host.Latest()
+	if !ok || snapshot.Filesystem == nil {
+		return container.Forecast{}, false
+	}
+
+	history, err := t.HistoryStore.GetDiskUsageHistory(now.Add(-diskForecastWindow), now)
+	if err != nil || len(history) == 0 {
+		return container.Forecast{}, false
+	}
+
+	times := make([]time.Time, len(history))
+	values := make([]float64, len(history))
+	for i, usage := range history {
+		times[i] = usage.Timestamp
+		values[i] = float64(usage.TotalSize) / (1024 * 1024)
+	}
+
+	// Docker can grow into whatever the filesystem has left
+	limit := values[len(values)-1] + snapshot.Filesystem.Available
+
+	forecast := container.ForecastExhaustion(times, values, limit, now, t.Horizon)
+	forecast.Kind = "disk"
+	forecast.Path = snapshot.Filesystem.Path
+	return forecast, true
+}
+
+// recordAlerts stores an event for every forecast entering or leaving the alert horizon
+func (t *ForecastTracker) recordAlerts(forecasts []container.Forecast, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, forecast := range forecasts {
+		key := forecast.ID
+		if forecast.Kind == "disk" {
+			key = diskForecastKey
+		}
+		seen[key] = true
+
+		if forecast.Alerting == t.alerting[key] {
+			continue
+		}
+		t.alerting[key] = forecast.Alerting
+
+		eventType := "forecast_alert"
+		if !forecast.Alerting {
+			eventType = "forecast_resolved"
+		}
+		t.recordEvent(forecastEvent(forecast, eventType, now))
+	}
+
+	// Containers that went away no longer alert
+	for key := range t.alerting {
+		if !seen[key] {
+			delete(t.alerting, key)
+		}
+	}
+}
+
+// recordEvent stores an event, logging failures
+func (t *ForecastTracker) recordEvent(event storage.ContainerEvent) {
+	if err := t.HistoryStore.AddEvent(event); err != nil {
+		log.Printf("Error recording %s event: %v", event.EventType, err)
+	}
+}
+
+// Latest returns the most recent forecasts and when they were computed
+func (t *ForecastTracker) Latest() ([]container.Forecast, time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make([]container.Forecast, len(t.forecasts))
+	copy(result, t.forecasts)
+	return result, t.updated
+}
+
+// forecastEvent builds a history event describing a forecast alert transition
+func forecastEvent(forecast container.Forecast, eventType string, now time.Time) storage.ContainerEvent {
+	details := map[string]string{
+		"kind":            forecast.Kind,
+		"current":         strconv.FormatFloat(forecast.Current, 'f', 1, 64),
+		"limit":           strconv.FormatFloat(forecast.Limit, 'f', 1, 64),
+		"growth_per_hour": strconv.FormatFloat(forecast.GrowthPerHour, 'f', 2, 64),
+	}
+	if forecast.ExhaustionAt != nil {
+		details["exhaustion_at"] = forecast.ExhaustionAt.Format(time.RFC3339)
+	}
+	if forecast.Path != "" {
+		details["path"] = forecast.Path
+	}
+
+	return storage.ContainerEvent{
+		ContainerID:   forecast.ID,
+		ContainerName: forecast.Name,
+		EventType:     eventType,
+		Timestamp:     now,
+		Details:       details,
+	}
+}
@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gocontainerops/internal/host"
+	"gocontainerops/internal/storage"
+)
+
+// HostTracker periodically samples host metrics and records them in history
+type HostTracker struct {
+	Collector    *host.Collector
+	HistoryStore storage.HistoryStore
+	Interval     time.Duration
+}
+
+// NewHostTracker creates a new host metrics tracker
+func NewHostTracker(collector *host.Collector, historyStore storage.HistoryStore, interval time.Duration) *HostTracker {
+	return &HostTracker{
+		Collector:    collector,
+		HistoryStore: historyStore,
+		Interval:     interval,
+	}
+}
+
+// Run samples immediately and then on every interval until the context is cancelled
+func (t *HostTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := t.Collector.Collect(time.Now())
+		if err != nil {
+			log.Printf("Error collecting host metrics: %v", err)
+		} else if err := t.HistoryStore.AddHostMetric(snapshot.Metric()); err != nil {
+			log.Printf("Error recording host metrics: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/docker"
+	"gocontainerops/internal/storage"
+)
+
+// Sampler builds a snapshot of every container at a fixed cadence from the stats
+// manager's latest samples and feeds it to the detectors, so detection does not
+// depend on clients polling the API. It also records downsampled metrics of every
+// running container, one rollup per RollupInterval. Restart counts and health come
+// from the watcher, so a sample costs the daemon a single container list.
+type Sampler struct {
+	DockerService  docker.DockerService
+	HistoryStore   storage.HistoryStore
+	Stats          *StatsManager
+	Watcher        *Watcher
+	CrashLoops     *container.CrashLoopDetector
+	Anomalies      *container.AnomalyDetector
+	Interval       time.Duration
+	RollupInterval time.Duration
+
+	// Rollups being accumulated per container, only touched by Sample
+	buckets map[string]*rollupBucket
+}
+
+// rollupBucket accumulates the samples of one container until it is flushed as a rollup
+type rollupBucket struct {
+	start   time.Time
+	samples int
+	cpuSum  float64
+	memSum  float64
+	pctSum  float64
+	last    container.ContainerData
+	lastAt  time.Time
+	cpuPeak float64
+	memPeak float64
+}
+
+// NewSampler creates a new sampler. A zero rollup interval disables rollups.
+func NewSampler(ds docker.DockerService, historyStore storage.HistoryStore, stats *StatsManager, interval, rollupInterval time.Duration) *Sampler {
+	return &Sampler{
+		DockerService:  ds,
+		HistoryStore:   historyStore,
+		Stats:          stats,
+		Interval:       interval,
+		RollupInterval: rollupInterval,
+		buckets:        make(map[string]*rollupBucket),
+	}
+}
+
+// Run samples immediately and then on every interval until the context is cancelled
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sample(ctx, time.Now()); err != nil {
+			log.Printf("Error sampling containers: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample collects every container once and runs the detectors on the result
+func (s *Sampler) Sample(ctx context.Context, now time.Time) error {
+	containers, err := s.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return err
+	}
+
+	results := make([]container.ContainerData, 0, len(containers))
+	var sampled []container.ContainerData // running containers with a fresh stats sample
+	for _, c := range containers {
+		restartCount, health, err := s.lifecycle(ctx, c.ID)
+		if err != nil {
+			continue
+		}
+
+		// Stopped and restarting containers have no stats; their restart count still matters
+		stats, hasStats := &types.StatsJSON{}, false
+		if c.State == "running" && s.Stats != nil {
+			if latest, ok := s.Stats.Latest(c.ID); ok {
+				stats, hasStats = latest, true
+			}
+		}
+
+		data := container.ProcessStats(c, stats, restartCount)
+		data.Health = health
+		results = append(results, data)
+		if hasStats {
+			sampled = append(sampled, data)
+		}
+	}
+
+	s.detectCrashLoops(results, now)
+	s.detectAnomalies(sampled, now)
+	s.rollup(sampled, now)
+
+	return nil
+}
+
+// lifecycle returns a container's restart count and health from the watcher, and
+// inspects containers the watcher does not know yet
+func (s *Sampler) lifecycle(ctx context.Context, containerID string) (int, *container.HealthInfo, error) {
+	if s.Watcher != nil {
+		if restartCount, health, ok := s.Watcher.Lifecycle(containerID[:12]); ok {
+			return restartCount, health, nil
+		}
+	}
+
+	info, err := s.DockerService.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return 0, nil, err
+	}
+	return info.RestartCount, extractHealth(info), nil
+}
+
+// rollup adds the sample to each container's bucket and stores the buckets that
+// span a full rollup interval. Buckets of containers that stopped are stored as is.
+func (s *Sampler) rollup(sampled []container.ContainerData, now time.Time) {
+	if s.HistoryStore == nil || s.RollupInterval <= 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(sampled))
+	for _, data := range sampled {
+		seen[data.ID] = true
+
+		bucket, ok := s.buckets[data.ID]
+		if !ok {
+			bucket = &rollupBucket{start: now}
+			s.buckets[data.ID] = bucket
+		}
+		bucket.samples++
+		bucket.cpuSum += data.CPUPercent
+		bucket.memSum += data.MemUsage
+		bucket.pctSum += data.MemPercent
+		bucket.cpuPeak = math.Max(bucket.cpuPeak, data.CPUPercent)
+		bucket.memPeak = math.Max(bucket.memPeak, data.MemUsage)
+		bucket.last = data
+		bucket.lastAt = now
+
+		if now.Sub(bucket.start) >= s.RollupInterval {
+			s.flush(data.ID, bucket)
+		}
+	}
+
+	for id, bucket := range s.buckets {
+		if !seen[id] {
+			s.flush(id, bucket)
+		}
+	}
+}
+
+// flush stores a bucket as a rollup stamped with its last sample and removes it
+func (s *Sampler) flush(id string, bucket *rollupBucket) {
+	delete(s.buckets, id)
+
+	n := float64(bucket.samples)
+	err := s.HistoryStore.AddMetricRollup(storage.MetricSnapshot{
+		ContainerID: id,
+		Timestamp:   bucket.lastAt,
+		CPUPercent:  bucket.cpuSum / n,
+		MemUsage:    bucket.memSum / n,
+		MemLimit:    bucket.last.MemLimit,
+		MemPercent:  bucket.pctSum / n,
+		NetInput:    bucket.last.NetInput, // cumulative counters
+		NetOutput:   bucket.last.NetOutput,
+		CPUPeak:     bucket.cpuPeak,
+		MemPeak:     bucket.memPeak,
+		Name:        bucket.last.Name,
+		Image:       bucket.last.Image,
+		Labels:      bucket.last.Labels,
+	})
+	if err != nil {
+		log.Printf("Error recording metric rollup for %s: %v", id, err)
+	}
+}
+
+// detectCrashLoops feeds the sample into the crash-loop detector. Containers entering
+// or leaving a crash loop are recorded as events in the history store.
+func (s *Sampler) detectCrashLoops(results []container.ContainerData, now time.Time) {
+	if s.CrashLoops == nil {
+		return
+	}
+
+	s.CrashLoops.Observe(results, now)
+	if s.HistoryStore != nil {
+		s.observeRestartEvents(now)
+	}
+
+	_, started, resolved := s.CrashLoops.Evaluate(now)
+
+	if s.HistoryStore != nil {
+		for _, info := range started {
+			s.recordEvent(crashLoopEvent(info, "crash_loop", now))
+		}
+		for _, info := range resolved {
+			s.recordEvent(crashLoopEvent(info, "crash_loop_resolved", now))
+		}
+	}
+}
+
+// observeRestartEvents passes recent restart events of the most restarted containers to the detector
+func (s *Sampler) observeRestartEvents(now time.Time) {
+	cutoff := now.Add(-s.CrashLoops.Config().RestartWindow)
+
+	restarted, err := s.HistoryStore.GetMostRestartedContainers(50)
+	if err != nil {
+		return
+	}
+
+	for _, stats := range restarted {
+		if stats.LastRestart.Before(cutoff) {
+			continue
+		}
+
+		events, err := s.HistoryStore.GetEvents(stats.ContainerID, 100)
+		if err != nil {
+			continue
+		}
+
+		// Events come newest first; the detector expects chronological order
+		var timestamps []time.Time
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].EventType == "restart" && !events[i].Timestamp.Before(cutoff) {
+				timestamps = append(timestamps, events[i].Timestamp)
+			}
+		}
+		s.CrashLoops.ObserveRestarts(stats.ContainerID, stats.ContainerName, timestamps)
+	}
+}
+
+// detectAnomalies feeds the sample into the anomaly detector. Anomalies starting
+// or resolving are recorded as events in the history store.
+func (s *Sampler) detectAnomalies(sampled []container.ContainerData, now time.Time) {
+	if s.Anomalies == nil {
+		return
+	}
+
+	started, resolved := s.Anomalies.Observe(sampled, now)
+
+	if s.HistoryStore != nil {
+		for _, anomaly := range started {
+			s.recordEvent(anomalyEvent(anomaly, "anomaly", now))
+		}
+		for _, anomaly := range resolved {
+			s.recordEvent(anomalyEvent(anomaly, "anomaly_resolved", now))
+		}
+	}
+}
+
+// recordEvent stores an event, logging failures
+func (s *Sampler) recordEvent(event storage.ContainerEvent) {
+	if err := s.HistoryStore.AddEvent(event); err != nil {
+		log.Printf("Error recording %s event: %v", event.EventType, err)
+	}
+}
+
+// crashLoopEvent builds a history event describing a crash-loop transition
+func crashLoopEvent(info container.CrashLoopInfo, eventType string, now time.Time) storage.ContainerEvent {
+	details := map[string]string{
+		"restarts":     strconv.Itoa(info.Restarts),
+		"health_flaps": strconv.Itoa(info.HealthFlaps),
+	}
+	if info.Reason != "" {
+		details["reason"] = info.Reason
+		details["backoff_seconds"] = strconv.FormatFloat(info.BackoffSeconds, 'f', 1, 64)
+	}
+
+	return storage.ContainerEvent{
+		ContainerID:   info.ID,
+		ContainerName: info.Name,
+		EventType:     eventType,
+		Timestamp:     now,
+		Details:       details,
+	}
+}
+
+// anomalyEvent builds a history event describing an anomaly transition
+func anomalyEvent(anomaly container.Anomaly, eventType string, now time.Time) storage.ContainerEvent {
+	return storage.ContainerEvent{
+		ContainerID:   anomaly.ID,
+		ContainerName: anomaly.Name,
+		EventType:     eventType,
+		Timestamp:     now,
+		Details: map[string]string{
+			"metric":   anomaly.Metric,
+			"value":    strconv.FormatFloat(anomaly.Value, 'f', 2, 64),
+			"expected": strconv.FormatFloat(anomaly.Expected, 'f', 2, 64),
+			"lower":    strconv.FormatFloat(anomaly.Lower, 'f', 2, 64),
+			"upper":    strconv.FormatFloat(anomaly.Upper, 'f', 2, 64),
+			"score":    strconv.FormatFloat(anomaly.Score, 'f', 2, 64),
+		},
+	}
+}
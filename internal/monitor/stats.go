@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/docker"
+)
+
+// statsResyncInterval is how often the manager reconciles its streams with the
+// running containers, in case a lifecycle event was missed
+const statsResyncInterval = 30 * time.Second
+
+// statsStaleAfter is how old a sample may get before callers fall back to a one-shot
+// stats call; the daemon normally sends a sample every second
+const statsStaleAfter = 10 * time.Second
+
+// StatsManager keeps one streaming stats subscription per running container and
+// serves the latest decoded sample from memory, so requests never wait on the
+// daemon's one-shot stats computation
+type StatsManager struct {
+	DockerService docker.DockerService
+
+	mu      sync.RWMutex
+	streams map[string]*statsStream // keyed by full container ID
+}
+
+// statsStream is a single container's stats subscription
+type statsStream struct {
+	cancel  context.CancelFunc
+	latest  *types.StatsJSON
+	updated time.Time
+}
+
+// NewStatsManager creates a new stats manager
+func NewStatsManager(dockerService docker.DockerService) *StatsManager {
+	return &StatsManager{
+		DockerService: dockerService,
+		streams:       make(map[string]*statsStream),
+	}
+}
+
+// Run subscribes to every running container and periodically reconciles the
+// subscriptions until the context is cancelled. Lifecycle events call Start and
+// Stop in between.
+func (m *StatsManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(statsResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		m.Sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			m.stopAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync starts streams for running containers that lack one and stops streams of
+// containers that are no longer running
+func (m *StatsManager) Sync(ctx context.Context) {
+	containers, err := m.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		log.Printf("Error listing containers for stats streams: %v", err)
+		return
+	}
+
+	running := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		running[c.ID] = true
+		m.Start(ctx, c.ID)
+	}
+
+	m.mu.RLock()
+	var stale []string
+	for id := range m.streams {
+		if !running[id] {
+			stale = append(stale, id)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, id := range stale {
+		m.Stop(id)
+	}
+}
+
+// Start subscribes to a container's stats stream unless already subscribed
+func (m *StatsManager) Start(ctx context.Context, containerID string) {
+	m.mu.Lock()
+	if _, exists := m.streams[containerID]; exists {
+		m.mu.Unlock()
+		return
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream := &statsStream{cancel: cancel}
+	m.streams[containerID] = stream
+	m.mu.Unlock()
+
+	go m.consume(streamCtx, containerID, stream)
+}
+
+// Stop cancels a container's stats stream and forgets its last sample
+func (m *StatsManager) Stop(containerID string) {
+	m.mu.Lock()
+	stream, exists := m.streams[containerID]
+	delete(m.streams, containerID)
+	m.mu.Unlock()
+
+	if exists {
+		stream.cancel()
+	}
+}
+
+// Latest returns the most recent stats sample of a container, if it has one
+func (m *StatsManager) Latest(containerID string) (*types.StatsJSON, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stream, exists := m.streams[containerID]
+	if !exists || stream.latest == nil || time.Since(stream.updated) > statsStaleAfter {
+		return nil, false
+	}
+	return stream.latest, true
+}
+
+// consume decodes samples until the stream ends, then drops the subscription so
+// the next Start or Sync can resubscribe
+func (m *StatsManager) consume(ctx context.Context, containerID string, stream *statsStream) {
+	defer func() {
+		m.mu.Lock()
+		if m.streams[containerID] == stream {
+			delete(m.streams, containerID)
+		}
+		m.mu.Unlock()
+		stream.cancel()
+	}()
+
+	reader, err := m.DockerService.ContainerStatsStream(ctx, containerID)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Error streaming stats for %s: %v", containerID[:12], err)
+		}
+		return
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var stats types.StatsJSON
+		if err := decoder.Decode(&stats); err != nil {
+			return
+		}
+
+		m.mu.Lock()
+		stream.latest = &stats
+		stream.updated = time.Now()
+		m.mu.Unlock()
+	}
+}
+
+// stopAll cancels every stream
+func (m *StatsManager) stopAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, stream := range m.streams {
+		stream.cancel()
+		delete(m.streams, id)
+	}
+}
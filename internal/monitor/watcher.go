@@ -0,0 +1,483 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+
+	"gocontainerops/internal/container"
+	"gocontainerops/internal/docker"
+	"gocontainerops/internal/storage"
+)
+
+// reconnectDelay is how long the watcher waits before re-subscribing to the event stream
+const reconnectDelay = 5 * time.Second
+
+// requestedStopWindow is how long after a kill event a death still counts as
+// requested; docker stop waits 10 seconds by default before its SIGKILL
+const requestedStopWindow = time.Minute
+
+// Watcher follows the Docker event stream and records container lifecycle
+// events and classified exits in the history store
+type Watcher struct {
+	DockerService docker.DockerService
+	HistoryStore  storage.HistoryStore
+	CrashLoops    *container.CrashLoopDetector
+	Anomalies     *container.AnomalyDetector
+	Stats         *StatsManager
+
+	mu            sync.Mutex
+	restartCounts map[string]int
+	oomKilled     map[string]bool
+	lastExits     map[string]time.Time
+	lastHealth    map[string]string
+	kills         map[string]time.Time
+	health        map[string]*container.HealthInfo
+}
+
+// NewWatcher creates a new event watcher
+func NewWatcher(dockerService docker.DockerService, historyStore storage.HistoryStore) *Watcher {
+	return &Watcher{
+		DockerService: dockerService,
+		HistoryStore:  historyStore,
+		restartCounts: make(map[string]int),
+		oomKilled:     make(map[string]bool),
+		lastExits:     make(map[string]time.Time),
+		lastHealth:    make(map[string]string),
+		kills:         make(map[string]time.Time),
+		health:        make(map[string]*container.HealthInfo),
+	}
+}
+
+// Run consumes Docker events until the context is cancelled.
+// When the event stream drops (usually because the daemon restarted), the watcher
+// reconnects and classifies containers that died during the outage as daemon restarts.
+func (w *Watcher) Run(ctx context.Context) {
+	var disconnectedAt time.Time
+
+	w.seed(ctx)
+
+	for {
+		if !disconnectedAt.IsZero() {
+			w.recordDaemonRestartExits(ctx, disconnectedAt)
+		}
+
+		err := w.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Docker event stream interrupted: %v", err)
+		disconnectedAt = time.Now()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// seed records current restart counts, health states and configurations so the first
+// policy restart, health transition or redeployment after startup is recognised, and
+// each container's last start and stop so availability covers it from the start
+func (w *Watcher) seed(ctx context.Context) {
+	containers, err := w.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		log.Printf("Error listing containers: %v", err)
+		return
+	}
+
+	for _, c := range containers {
+		info, err := w.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			continue
+		}
+		if info.State != nil {
+			startedAt, _ := time.Parse(time.RFC3339Nano, info.State.StartedAt)
+			finishedAt, _ := time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+			if info.State.Running {
+				w.recordConfig(info, startedAt)
+			}
+
+			// Containers already up (or stopped) at startup otherwise stay out of
+			// availability reports until their next lifecycle event
+			if w.HistoryStore != nil {
+				name := strings.TrimPrefix(info.Name, "/")
+				if err := w.HistoryStore.SeedTransitions(c.ID[:12], name, startedAt, finishedAt, info.State.Running); err != nil {
+					log.Printf("Error seeding availability of %s: %v", name, err)
+				}
+			}
+		}
+
+		w.mu.Lock()
+		w.restartCounts[c.ID[:12]] = info.RestartCount
+		if info.State != nil && info.State.Health != nil {
+			w.lastHealth[c.ID[:12]] = info.State.Health.Status
+		}
+		w.health[c.ID[:12]] = extractHealth(info)
+		w.mu.Unlock()
+	}
+}
+
+// Lifecycle returns a container's restart count and health as last seen by the
+// watcher, which refreshes them on start and health events. It reports false for
+// containers it has not inspected yet, which callers then inspect themselves.
+func (w *Watcher) Lifecycle(id string) (int, *container.HealthInfo, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	restartCount, known := w.restartCounts[id]
+	return restartCount, w.health[id], known
+}
+
+// consume reads from a single event stream subscription until it fails
+func (w *Watcher) consume(ctx context.Context) error {
+	options := types.EventsOptions{
+		Filters: filters.NewArgs(filters.Arg("type", events.ContainerEventType)),
+	}
+
+	messages, errs := w.DockerService.Events(ctx, options)
+	for {
+		select {
+		case msg := <-messages:
+			w.handleEvent(ctx, msg)
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// handleEvent records a single container event
+func (w *Watcher) handleEvent(ctx context.Context, msg events.Message) {
+	if len(msg.Actor.ID) < 12 {
+		return
+	}
+
+	id := msg.Actor.ID[:12]
+	name := msg.Actor.Attributes["name"]
+	timestamp := time.Unix(0, msg.TimeNano)
+	if msg.TimeNano == 0 {
+		timestamp = time.Unix(msg.Time, 0)
+	}
+
+	// Health reports arrive as "health_status: <status>"
+	if strings.HasPrefix(msg.Action, "health_status:") {
+		status := strings.TrimSpace(strings.TrimPrefix(msg.Action, "health_status:"))
+		w.refreshHealth(ctx, msg.Actor.ID, id, status)
+		w.handleHealth(id, name, status, timestamp)
+		return
+	}
+
+	switch msg.Action {
+	case "start":
+		if w.Stats != nil {
+			w.Stats.Start(ctx, msg.Actor.ID)
+		}
+		w.handleStart(ctx, msg.Actor.ID, id, name, timestamp)
+	case "restart":
+		w.addEvent(storage.ContainerEvent{
+			ContainerID:   id,
+			ContainerName: name,
+			EventType:     "restart",
+			Timestamp:     timestamp,
+			RestartCount:  w.restartCount(id),
+		})
+	case "update":
+		// Limits or restart policy changed in place (docker update or the API)
+		if info, err := w.DockerService.ContainerInspect(ctx, msg.Actor.ID); err == nil {
+			w.recordConfig(info, timestamp)
+		}
+	case "oom":
+		w.mu.Lock()
+		w.oomKilled[id] = true
+		w.mu.Unlock()
+	case "kill":
+		// Sent for docker stop, kill and restart, compose down and the project
+		// actions, but not when the process dies on its own
+		w.mu.Lock()
+		w.kills[id] = timestamp
+		w.mu.Unlock()
+	case "die":
+		if w.Stats != nil {
+			w.Stats.Stop(msg.Actor.ID)
+		}
+		w.handleDie(ctx, msg, id, name, timestamp)
+	case "destroy":
+		if w.Stats != nil {
+			w.Stats.Stop(msg.Actor.ID)
+		}
+		w.mu.Lock()
+		delete(w.restartCounts, id)
+		delete(w.oomKilled, id)
+		delete(w.lastExits, id)
+		delete(w.lastHealth, id)
+		delete(w.kills, id)
+		delete(w.health, id)
+		w.mu.Unlock()
+		if w.CrashLoops != nil {
+			w.CrashLoops.Forget(id)
+		}
+		if w.Anomalies != nil {
+			w.Anomalies.Forget(id)
+		}
+	}
+}
+
+// handleStart records a start, or a restart when the daemon's RestartCount went up
+func (w *Watcher) handleStart(ctx context.Context, fullID, id, name string, timestamp time.Time) {
+	eventType := "start"
+	restartCount := 0
+
+	info, err := w.DockerService.ContainerInspect(ctx, fullID)
+	if err == nil {
+		restartCount = info.RestartCount
+		w.recordConfig(info, timestamp)
+
+		w.mu.Lock()
+		previous, known := w.restartCounts[id]
+		w.restartCounts[id] = restartCount
+		w.health[id] = extractHealth(info)
+		w.mu.Unlock()
+
+		if known && restartCount > previous {
+			eventType = "restart"
+		}
+	} else {
+		log.Printf("Error inspecting container %s: %v", id, err)
+	}
+
+	w.addEvent(storage.ContainerEvent{
+		ContainerID:   id,
+		ContainerName: name,
+		EventType:     eventType,
+		Timestamp:     timestamp,
+		RestartCount:  restartCount,
+	})
+}
+
+// recordConfig stores a configuration snapshot and records a config_change event
+// when it differs from the previous deployment of the same container or service
+func (w *Watcher) recordConfig(info types.ContainerJSON, timestamp time.Time) {
+	if w.HistoryStore == nil || info.ContainerJSONBase == nil {
+		return
+	}
+
+	snapshot, changed, err := w.HistoryStore.AddConfigSnapshot(buildConfigSnapshot(info, timestamp))
+	if err != nil {
+		log.Printf("Error storing config snapshot for %s: %v", snapshot.Key, err)
+		return
+	}
+	if !changed || snapshot.Version == 1 {
+		return
+	}
+
+	fields := make([]string, 0, len(snapshot.Changes))
+	for _, change := range snapshot.Changes {
+		fields = append(fields, change.Field)
+	}
+
+	w.addEvent(storage.ContainerEvent{
+		ContainerID:   snapshot.ContainerID,
+		ContainerName: snapshot.ContainerName,
+		EventType:     "config_change",
+		Timestamp:     timestamp,
+		RestartCount:  info.RestartCount,
+		Details: map[string]string{
+			"key":     snapshot.Key,
+			"version": strconv.Itoa(snapshot.Version),
+			"changes": strings.Join(fields, ","),
+		},
+	})
+}
+
+// refreshHealth re-reads a container's health after a probe so the failing streak and
+// probe log stay current; when inspect fails only the reported status is kept
+func (w *Watcher) refreshHealth(ctx context.Context, fullID, id, status string) {
+	health := &container.HealthInfo{Status: status}
+	if info, err := w.DockerService.ContainerInspect(ctx, fullID); err == nil {
+		if extracted := extractHealth(info); extracted != nil {
+			health = extracted
+		}
+	} else {
+		log.Printf("Error inspecting container %s: %v", id, err)
+	}
+
+	w.mu.Lock()
+	w.health[id] = health
+	w.mu.Unlock()
+}
+
+// extractHealth returns the health check state of an inspected container, if it has one
+func extractHealth(info types.ContainerJSON) *container.HealthInfo {
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return nil
+	}
+	return container.ExtractHealth(info.State.Health)
+}
+
+// handleHealth records health status transitions; repeated reports of the same status are ignored
+func (w *Watcher) handleHealth(id, name, status string, timestamp time.Time) {
+	if w.CrashLoops != nil {
+		w.CrashLoops.ObserveHealth(id, name, status, timestamp)
+	}
+
+	w.mu.Lock()
+	previous := w.lastHealth[id]
+	w.lastHealth[id] = status
+	w.mu.Unlock()
+
+	if previous == status {
+		return
+	}
+
+	details := map[string]string{"status": status}
+	if previous != "" {
+		details["previous_status"] = previous
+	}
+
+	w.addEvent(storage.ContainerEvent{
+		ContainerID:   id,
+		ContainerName: name,
+		EventType:     "health_status",
+		Timestamp:     timestamp,
+		RestartCount:  w.restartCount(id),
+		Details:       details,
+	})
+}
+
+// handleDie classifies a container death and records it as a stop event and an exit
+func (w *Watcher) handleDie(ctx context.Context, msg events.Message, id, name string, timestamp time.Time) {
+	// The event's exit code is authoritative: a container with a restart policy is
+	// usually running again by the time it is inspected, and starting it resets
+	// State.ExitCode and State.OOMKilled
+	exitCode, err := strconv.Atoi(msg.Actor.Attributes["exitCode"])
+	hasExitCode := err == nil
+
+	w.mu.Lock()
+	oomKilled := w.oomKilled[id]
+	delete(w.oomKilled, id)
+	killedAt, killed := w.kills[id]
+	delete(w.kills, id)
+	w.mu.Unlock()
+	requested := killed && !timestamp.Before(killedAt) && timestamp.Sub(killedAt) <= requestedStopWindow
+
+	// Inspect only adds to the event while the container is still down (it is gone
+	// for --rm containers), and never turns a failure into a clean exit
+	if info, err := w.DockerService.ContainerInspect(ctx, msg.Actor.ID); err == nil && info.State != nil && !info.State.Running && !info.State.Restarting {
+		if !hasExitCode || exitCode == 0 {
+			exitCode = info.State.ExitCode
+		}
+		oomKilled = oomKilled || info.State.OOMKilled
+	}
+
+	classification := container.ClassifyExit(exitCode, oomKilled)
+	w.recordExit(id, name, msg.Actor.Attributes["image"], timestamp, classification, requested && !oomKilled)
+}
+
+// recordDaemonRestartExits classifies containers that stopped while the event stream was down
+func (w *Watcher) recordDaemonRestartExits(ctx context.Context, disconnectedAt time.Time) {
+	containers, err := w.DockerService.ListContainers(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		log.Printf("Error listing containers after reconnect: %v", err)
+		return
+	}
+
+	for _, c := range containers {
+		info, err := w.DockerService.ContainerInspect(ctx, c.ID)
+		if err != nil || info.State == nil {
+			continue
+		}
+
+		finishedAt, err := time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+		if err != nil || finishedAt.Before(disconnectedAt) {
+			continue
+		}
+
+		// Skip deaths the stream already reported before it went down
+		id := c.ID[:12]
+		w.mu.Lock()
+		lastExit := w.lastExits[id]
+		w.mu.Unlock()
+		if !lastExit.Before(finishedAt.Add(-time.Second)) {
+			continue
+		}
+
+		name := "unknown"
+		if len(c.Names) > 0 {
+			name = c.Names[0][1:] // Remove leading slash
+		}
+
+		classification := container.ClassifyExit(info.State.ExitCode, info.State.OOMKilled)
+		if !info.State.OOMKilled {
+			classification.Reason = container.ExitReasonDaemonRestart
+		}
+		w.recordExit(id, name, c.Image, finishedAt, classification, false)
+	}
+}
+
+// recordExit stores a classified exit together with the matching stop event.
+// Requested exits followed a kill from docker stop or kill; they are not crashes
+// and availability counts them as planned downtime.
+func (w *Watcher) recordExit(id, name, image string, timestamp time.Time, classification container.ExitClassification, requested bool) {
+	w.mu.Lock()
+	w.lastExits[id] = timestamp
+	w.mu.Unlock()
+
+	if w.HistoryStore == nil {
+		return
+	}
+
+	details := map[string]string{
+		"exit_code": strconv.Itoa(classification.ExitCode),
+		"reason":    string(classification.Reason),
+	}
+	if classification.Signal != "" {
+		details["signal"] = classification.Signal
+	}
+	if requested {
+		details["requested"] = "true"
+	}
+
+	w.addEvent(storage.ContainerEvent{
+		ContainerID:   id,
+		ContainerName: name,
+		EventType:     "stop",
+		Timestamp:     timestamp,
+		RestartCount:  w.restartCount(id),
+		Details:       details,
+	})
+
+	w.HistoryStore.AddExit(storage.ExitRecord{
+		ContainerID:   id,
+		ContainerName: name,
+		Image:         image,
+		Timestamp:     timestamp,
+		ExitCode:      classification.ExitCode,
+		Reason:        string(classification.Reason),
+		Signal:        classification.Signal,
+		Requested:     requested,
+		Crash:         classification.Reason.IsCrash() && !requested,
+	})
+}
+
+// addEvent stores an event when a history store is configured
+func (w *Watcher) addEvent(event storage.ContainerEvent) {
+	if w.HistoryStore == nil {
+		return
+	}
+	w.HistoryStore.AddEvent(event)
+}
+
+// restartCount returns the last known restart count for a container
+func (w *Watcher) restartCount(id string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.restartCounts[id]
+}
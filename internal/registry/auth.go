@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerHubAuthKey is the key Docker stores Docker Hub credentials under
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Credentials holds a username and password or identity token for a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialStore resolves registry credentials by registry host
+type CredentialStore interface {
+	Credentials(host string) (Credentials, bool)
+}
+
+// DockerConfig is the subset of ~/.docker/config.json needed to authenticate
+type DockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+// dockerAuth is a single entry of the auths section
+type dockerAuth struct {
+	Auth          string `json:"auth"` // base64 "username:password"
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// LoadDockerConfig reads the Docker client configuration from $DOCKER_CONFIG or ~/.docker.
+// A missing file yields an empty configuration.
+func LoadDockerConfig() (*DockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return &DockerConfig{}, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	return LoadDockerConfigFile(filepath.Join(dir, "config.json"))
+}
+
+// LoadDockerConfigFile reads a Docker client configuration file
+func LoadDockerConfigFile(path string) (*DockerConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &DockerConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config DockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return &config, nil
+}
+
+// Credentials looks up the credentials for a registry host, consulting
+// credential helpers before the inline auths section
+func (c *DockerConfig) Credentials(host string) (Credentials, bool) {
+	key := host
+	if host == "docker.io" || host == "registry-1.docker.io" || host == "index.docker.io" {
+		key = dockerHubAuthKey
+	}
+
+	helper := c.CredHelpers[host]
+	if helper == "" {
+		helper = c.CredsStore
+	}
+	if helper != "" {
+		if creds, ok := helperCredentials(helper, key); ok {
+			return creds, true
+		}
+	}
+
+	for server, auth := range c.Auths {
+		if normalizeServer(server) != normalizeServer(key) {
+			continue
+		}
+		return auth.credentials()
+	}
+
+	return Credentials{}, false
+}
+
+// credentials decodes an auths entry
+func (a dockerAuth) credentials() (Credentials, bool) {
+	if a.IdentityToken != "" {
+		return Credentials{Username: "<token>", Password: a.IdentityToken}, true
+	}
+	if a.Username != "" {
+		return Credentials{Username: a.Username, Password: a.Password}, true
+	}
+	if a.Auth == "" {
+		return Credentials{}, false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return Credentials{}, false
+	}
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return Credentials{}, false
+	}
+	return Credentials{Username: username, Password: password}, true
+}
+
+// helperCredentials runs docker-credential-<helper> get for a server
+func helperCredentials(helper, server string) (Credentials, bool) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, false
+	}
+
+	var response struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &response); err != nil || response.Secret == "" {
+		return Credentials{}, false
+	}
+	return Credentials{Username: response.Username, Password: response.Secret}, true
+}
+
+// normalizeServer strips the scheme and path so "https://host/v1/" matches "host"
+func normalizeServer(server string) string {
+	if server == dockerHubAuthKey {
+		return server
+	}
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	host, _, _ := strings.Cut(server, "/")
+	return host
+}
@@ -0,0 +1,208 @@
+package registry
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+
+	"gocontainerops/internal/docker"
+)
+
+// UpdateStatus reports whether a container's image tag has moved upstream
+type UpdateStatus struct {
+	ContainerID     string    `json:"container_id"`
+	ContainerName   string    `json:"container_name"`
+	Image           string    `json:"image"`
+	LocalDigest     string    `json:"local_digest,omitempty"`
+	RemoteDigest    string    `json:"remote_digest,omitempty"`
+	UpdateAvailable bool      `json:"update_available"`
+	NewerTag        string    `json:"newer_tag,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Checker periodically compares running containers' image digests with the registry
+type Checker struct {
+	DockerService docker.DockerService
+	Registry      *Client
+	Interval      time.Duration
+	// Semver also looks for newer version tags in the repository
+	Semver bool
+
+	mu        sync.RWMutex
+	statuses  []UpdateStatus
+	lastCheck time.Time
+}
+
+// NewChecker creates a new update checker
+func NewChecker(ds docker.DockerService, registry *Client, interval time.Duration, semver bool) *Checker {
+	return &Checker{
+		DockerService: ds,
+		Registry:      registry,
+		Interval:      interval,
+		Semver:        semver,
+	}
+}
+
+// Run checks immediately and then on every interval until the context is cancelled
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Check(ctx); err != nil {
+			log.Printf("Error checking for image updates: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check resolves the tag of every running container against the registry.
+// Each distinct image reference is queried once per check.
+func (c *Checker) Check(ctx context.Context) error {
+	containers, err := c.DockerService.ListContainers(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return err
+	}
+
+	images, err := c.DockerService.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return err
+	}
+	repoDigests := make(map[string][]string)
+	for _, img := range images {
+		repoDigests[img.ID] = img.RepoDigests
+	}
+
+	now := time.Now()
+	remote := make(map[string]UpdateStatus)
+	statuses := make([]UpdateStatus, 0, len(containers))
+
+	for _, ctr := range containers {
+		name := "unknown"
+		if len(ctr.Names) > 0 {
+			name = ctr.Names[0][1:] // Remove leading slash
+		}
+		status := UpdateStatus{
+			ContainerID:   ctr.ID[:12],
+			ContainerName: name,
+			Image:         c.imageReference(ctx, ctr),
+			CheckedAt:     now,
+		}
+
+		named, err := reference.ParseNormalizedNamed(status.Image)
+		if err != nil {
+			status.Error = "image is not a registry reference"
+			statuses = append(statuses, status)
+			continue
+		}
+		tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+		if !ok {
+			// Pinned by digest, nothing to compare against
+			status.Error = "image is pinned by digest"
+			statuses = append(statuses, status)
+			continue
+		}
+
+		status.LocalDigest = localDigest(tagged, repoDigests[ctr.ImageID])
+		if status.LocalDigest == "" {
+			status.Error = "image has no registry digest (built or loaded locally)"
+			statuses = append(statuses, status)
+			continue
+		}
+
+		upstream, cached := remote[tagged.String()]
+		if !cached {
+			upstream = c.resolve(ctx, tagged)
+			remote[tagged.String()] = upstream
+		}
+
+		status.RemoteDigest = upstream.RemoteDigest
+		status.NewerTag = upstream.NewerTag
+		status.Error = upstream.Error
+		status.UpdateAvailable = status.RemoteDigest != "" && status.RemoteDigest != status.LocalDigest
+		statuses = append(statuses, status)
+	}
+
+	c.mu.Lock()
+	c.statuses = statuses
+	c.lastCheck = now
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Statuses returns the results of the latest check and when it ran
+func (c *Checker) Statuses() ([]UpdateStatus, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]UpdateStatus, len(c.statuses))
+	copy(result, c.statuses)
+	return result, c.lastCheck
+}
+
+// imageReference returns the reference a container was created from. Once its tag
+// has moved to a newer pull, the container list shows the bare image ID instead.
+func (c *Checker) imageReference(ctx context.Context, ctr types.Container) string {
+	if !strings.HasPrefix(ctr.Image, "sha256:") {
+		return ctr.Image
+	}
+
+	info, err := c.DockerService.ContainerInspect(ctx, ctr.ID)
+	if err != nil {
+		log.Printf("Error inspecting container %s: %v", ctr.ID[:12], err)
+		return ctr.Image
+	}
+	if info.Config == nil || info.Config.Image == "" {
+		return ctr.Image
+	}
+	return info.Config.Image
+}
+
+// resolve fetches the upstream digest, and the newest version tag when enabled
+func (c *Checker) resolve(ctx context.Context, ref reference.NamedTagged) UpdateStatus {
+	var result UpdateStatus
+
+	digest, err := c.Registry.Digest(ctx, ref)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.RemoteDigest = digest
+
+	if c.Semver {
+		tags, err := c.Registry.Tags(ctx, ref)
+		if err != nil {
+			log.Printf("Error listing tags of %s: %v", reference.FamiliarName(ref), err)
+		} else {
+			result.NewerTag = NewestTag(ref.Tag(), tags)
+		}
+	}
+
+	return result
+}
+
+// localDigest picks the RepoDigests entry belonging to the reference's repository
+func localDigest(ref reference.Named, digests []string) string {
+	for _, entry := range digests {
+		canonical, err := reference.ParseNormalizedNamed(entry)
+		if err != nil {
+			continue
+		}
+		if withDigest, ok := canonical.(reference.Canonical); ok && canonical.Name() == ref.Name() {
+			return withDigest.Digest().String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+
+	"gocontainerops/internal/docker"
+)
+
+// fakeDocker serves fixed containers, images and container configs; other calls are not used by the checker
+type fakeDocker struct {
+	docker.DockerService
+	containers []types.Container
+	images     []types.ImageSummary
+	configs    map[string]string // container ID to Config.Image
+}
+
+func (f *fakeDocker) ListContainers(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeDocker) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return f.images, nil
+}
+
+func (f *fakeDocker) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{Config: &dockercontainer.Config{Image: f.configs[containerID]}}, nil
+}
+
+func TestChecker_CompareDigests(t *testing.T) {
+	tr := newTestRegistry(t, "token-a", testDigest('b'))
+	tr.tags = [][]string{{"1.0", "1.1", "1.10", "1.2-alpine", "2.0.0", "latest"}}
+	repo := strings.TrimPrefix(tr.registry.URL, "http://") + "/team/app"
+
+	ds := &fakeDocker{
+		containers: []types.Container{
+			{ID: "aaaaaaaaaaaa0000", Names: []string{"/stale"}, Image: repo + ":1.0", ImageID: "sha256:old"},
+			{ID: "bbbbbbbbbbbb0000", Names: []string{"/current"}, Image: repo + ":1.0", ImageID: "sha256:new"},
+			{ID: "cccccccccccc0000", Names: []string{"/local"}, Image: "local-build:dev", ImageID: "sha256:local"},
+			// The tag was pulled again, so the list shows the old image's ID
+			{ID: "dddddddddddd0000", Names: []string{"/repulled"}, Image: "sha256:old", ImageID: "sha256:old"},
+		},
+		images: []types.ImageSummary{
+			{ID: "sha256:old", RepoDigests: []string{repo + "@" + testDigest('a')}},
+			{ID: "sha256:new", RepoDigests: []string{repo + "@" + testDigest('b')}},
+			{ID: "sha256:local"},
+		},
+		configs: map[string]string{"dddddddddddd0000": repo + ":1.0"},
+	}
+
+	checker := NewChecker(ds, NewClient("", nil), 0, true)
+	if err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	statuses, _ := checker.Statuses()
+	byName := make(map[string]UpdateStatus)
+	for _, status := range statuses {
+		byName[status.ContainerName] = status
+	}
+
+	stale := byName["stale"]
+	if !stale.UpdateAvailable || stale.LocalDigest != testDigest('a') || stale.RemoteDigest != testDigest('b') {
+		t.Errorf("stale: %+v", stale)
+	}
+	if stale.NewerTag != "1.10" {
+		t.Errorf("stale: newer tag %q, want 1.10", stale.NewerTag)
+	}
+
+	if repulled := byName["repulled"]; !repulled.UpdateAvailable || repulled.Image != repo+":1.0" || repulled.LocalDigest != testDigest('a') {
+		t.Errorf("repulled: %+v", repulled)
+	}
+
+	if current := byName["current"]; current.UpdateAvailable || current.Error != "" {
+		t.Errorf("current: %+v", current)
+	}
+	if local := byName["local"]; local.UpdateAvailable || local.Error == "" {
+		t.Errorf("local: %+v", local)
+	}
+
+	// The containers share a reference, which is resolved once per check
+	if issued := tr.tokensIssued.Load(); issued != 1 {
+		t.Errorf("token fetched %d times, want 1", issued)
+	}
+}
@@ -0,0 +1,317 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+)
+
+// manifestMediaTypes are accepted when resolving a tag so the returned digest
+// matches the one Docker records in an image's RepoDigests
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// Client queries the Docker Registry HTTP API V2 for manifest digests and tags
+type Client struct {
+	// HTTPClient performs the requests; http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	// Endpoint overrides the registry derived from each image reference, e.g.
+	// "http://localhost:5000" for a local registry:2 stand-in or mirror
+	Endpoint string
+
+	// Auth supplies credentials per registry host; anonymous access when nil
+	Auth CredentialStore
+
+	mu     sync.Mutex
+	tokens map[string]bearerToken // keyed by registry host and scope
+}
+
+// bearerToken is a cached registry token
+type bearerToken struct {
+	value   string
+	expires time.Time
+}
+
+// NewClient creates a registry client
+func NewClient(endpoint string, auth CredentialStore) *Client {
+	return &Client{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		Auth:       auth,
+		tokens:     make(map[string]bearerToken),
+	}
+}
+
+// Digest resolves a tag to the digest of its manifest (or manifest list)
+func (c *Client) Digest(ctx context.Context, ref reference.NamedTagged) (string, error) {
+	endpoint := c.endpointFor(ref)
+	target := fmt.Sprintf("%s/v2/%s/manifests/%s", endpoint, reference.Path(ref), ref.Tag())
+
+	resp, err := c.do(ctx, http.MethodHead, target, ref, func(req *http.Request) {
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry returned %s for %s", resp.Status, reference.FamiliarString(ref))
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry returned no digest for %s", reference.FamiliarString(ref))
+	}
+	return digest, nil
+}
+
+// Tags lists the tags of a repository
+func (c *Client) Tags(ctx context.Context, ref reference.Named) ([]string, error) {
+	endpoint := c.endpointFor(ref)
+	target := fmt.Sprintf("%s/v2/%s/tags/list", endpoint, reference.Path(ref))
+
+	var tags []string
+	for target != "" {
+		resp, err := c.do(ctx, http.MethodGet, target, ref, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("registry returned %s listing tags of %s", resp.Status, reference.FamiliarName(ref))
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding tag list: %v", err)
+		}
+		tags = append(tags, page.Tags...)
+
+		target = nextPage(resp, endpoint)
+	}
+
+	return tags, nil
+}
+
+// do sends a request, answering a 401 challenge once with basic or bearer auth
+func (c *Client) do(ctx context.Context, method, target string, ref reference.Named, prepare func(*http.Request)) (*http.Response, error) {
+	scope := fmt.Sprintf("repository:%s:pull", reference.Path(ref))
+
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		if prepare != nil {
+			prepare(req)
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	if token := c.cachedToken(req.URL.Host, scope); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenges := challenge.ResponseChallenges(resp)
+	resp.Body.Close()
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	creds, hasCreds := c.credentials(req.URL.Host, ref)
+	for _, ch := range challenges {
+		switch strings.ToLower(ch.Scheme) {
+		case "bearer":
+			token, err := c.fetchToken(ctx, req.URL.Host, ch.Parameters, scope, creds, hasCreds)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			return c.httpClient().Do(req)
+		case "basic":
+			if !hasCreds {
+				continue
+			}
+			req.SetBasicAuth(creds.Username, creds.Password)
+			return c.httpClient().Do(req)
+		}
+	}
+
+	return nil, fmt.Errorf("registry requires authentication for %s", reference.FamiliarName(ref))
+}
+
+// fetchToken requests a bearer token from the realm named in the challenge and
+// caches it for the registry host that issued the challenge
+func (c *Client) fetchToken(ctx context.Context, host string, params map[string]string, scope string, creds Credentials, hasCreds bool) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge without realm")
+	}
+
+	realmURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %v", realm, err)
+	}
+	query := realmURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", scope)
+	realmURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realmURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCreds {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token server returned %s", resp.Status)
+	}
+
+	var response struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("decoding token response: %v", err)
+	}
+
+	token := response.Token
+	if token == "" {
+		token = response.AccessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("token server returned no token")
+	}
+
+	// Tokens without an explicit lifetime are valid for 60 seconds per the token spec
+	lifetime := time.Duration(response.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = 60 * time.Second
+	}
+	c.mu.Lock()
+	if c.tokens == nil {
+		c.tokens = make(map[string]bearerToken)
+	}
+	c.tokens[tokenKey(host, scope)] = bearerToken{value: token, expires: time.Now().Add(lifetime - 5*time.Second)}
+	c.mu.Unlock()
+
+	return token, nil
+}
+
+// cachedToken returns an unexpired token for a scope on a registry host, if any
+func (c *Client) cachedToken(host, scope string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, exists := c.tokens[tokenKey(host, scope)]
+	if !exists || time.Now().After(token.expires) {
+		return ""
+	}
+	return token.value
+}
+
+// tokenKey identifies a cached token; the same repository path on two registries
+// needs two tokens
+func tokenKey(host, scope string) string {
+	return host + " " + scope
+}
+
+// credentials looks up credentials for the contacted host, falling back to the
+// reference's own registry when an endpoint override is in use
+func (c *Client) credentials(host string, ref reference.Named) (Credentials, bool) {
+	if c.Auth == nil {
+		return Credentials{}, false
+	}
+	if creds, ok := c.Auth.Credentials(host); ok {
+		return creds, true
+	}
+	return c.Auth.Credentials(reference.Domain(ref))
+}
+
+// endpointFor returns the base URL of the registry serving a reference
+func (c *Client) endpointFor(ref reference.Named) string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+
+	domain := reference.Domain(ref)
+	switch {
+	case domain == "docker.io":
+		return "https://registry-1.docker.io"
+	case strings.HasPrefix(domain, "localhost") || strings.HasPrefix(domain, "127.0.0.1"):
+		return "http://" + domain
+	default:
+		return "https://" + domain
+	}
+}
+
+// httpClient returns the configured HTTP client
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// nextPage follows the RFC 5988 Link header used to paginate tag lists
+func nextPage(resp *http.Response, endpoint string) string {
+	link := resp.Header.Get("Link")
+	if link == "" {
+		return ""
+	}
+
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end <= start || !strings.Contains(link[end:], `rel="next"`) {
+		return ""
+	}
+
+	next := link[start+1 : end]
+	if strings.HasPrefix(next, "/") {
+		return endpoint + next
+	}
+	return next
+}
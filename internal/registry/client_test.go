@@ -0,0 +1,135 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+)
+
+// testRegistry is a registry that answers manifest requests with a fixed digest once
+// the client presents the token issued by its own token server
+type testRegistry struct {
+	registry     *httptest.Server
+	tokenServer  *httptest.Server
+	token        string
+	digest       string
+	tags         [][]string // pages of the tag list
+	tokensIssued atomic.Int32
+}
+
+func newTestRegistry(t *testing.T, token, digest string) *testRegistry {
+	t.Helper()
+	tr := &testRegistry{token: token, digest: digest}
+
+	tr.tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("service") != "test-registry" || !strings.HasPrefix(r.URL.Query().Get("scope"), "repository:") {
+			http.Error(w, "bad token request", http.StatusBadRequest)
+			return
+		}
+		tr.tokensIssued.Add(1)
+		fmt.Fprintf(w, `{"token": %q, "expires_in": 300}`, tr.token)
+	}))
+	t.Cleanup(tr.tokenServer.Close)
+
+	tr.registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+tr.token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q,service="test-registry"`, tr.tokenServer.URL+"/token"))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/manifests/1.0"):
+			w.Header().Set("Docker-Content-Digest", tr.digest)
+		case strings.HasSuffix(r.URL.Path, "/tags/list"):
+			page := 0
+			fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+			if page+1 < len(tr.tags) {
+				w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+			}
+			tags := `"` + strings.Join(tr.tags[page], `","`) + `"`
+			fmt.Fprintf(w, `{"name": "app", "tags": [%s]}`, tags)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(tr.registry.Close)
+
+	return tr
+}
+
+// ref returns a reference to the app repository on the test registry
+func (tr *testRegistry) ref(t *testing.T, tag string) reference.NamedTagged {
+	t.Helper()
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(tr.registry.URL, "http://") + "/team/app:" + tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return named.(reference.NamedTagged)
+}
+
+func testDigest(c byte) string {
+	return "sha256:" + strings.Repeat(string(c), 64)
+}
+
+func TestClient_DigestBearerChallenge(t *testing.T) {
+	tr := newTestRegistry(t, "token-a", testDigest('a'))
+	client := NewClient("", nil)
+
+	for i := 0; i < 3; i++ {
+		digest, err := client.Digest(context.Background(), tr.ref(t, "1.0"))
+		if err != nil {
+			t.Fatalf("Digest: %v", err)
+		}
+		if digest != tr.digest {
+			t.Errorf("got digest %s, want %s", digest, tr.digest)
+		}
+	}
+
+	if issued := tr.tokensIssued.Load(); issued != 1 {
+		t.Errorf("token fetched %d times, want 1 (cached afterwards)", issued)
+	}
+}
+
+func TestClient_TokenCacheKeyedByHost(t *testing.T) {
+	first := newTestRegistry(t, "token-first", testDigest('1'))
+	second := newTestRegistry(t, "token-second", testDigest('2'))
+	client := NewClient("", nil)
+
+	// The same repository path on both registries shares a scope but not a token
+	for i := 0; i < 2; i++ {
+		for _, tr := range []*testRegistry{first, second} {
+			digest, err := client.Digest(context.Background(), tr.ref(t, "1.0"))
+			if err != nil {
+				t.Fatalf("Digest: %v", err)
+			}
+			if digest != tr.digest {
+				t.Errorf("got digest %s, want %s", digest, tr.digest)
+			}
+		}
+	}
+
+	if first.tokensIssued.Load() != 1 || second.tokensIssued.Load() != 1 {
+		t.Errorf("tokens fetched %d and %d times, want 1 each", first.tokensIssued.Load(), second.tokensIssued.Load())
+	}
+}
+
+func TestClient_TagsFollowsPages(t *testing.T) {
+	tr := newTestRegistry(t, "token-a", testDigest('a'))
+	tr.tags = [][]string{{"1.0", "1.1"}, {"1.2", "latest"}}
+	client := NewClient("", nil)
+
+	tags, err := client.Tags(context.Background(), tr.ref(t, "1.0"))
+	if err != nil {
+		t.Fatalf("Tags: %v", err)
+	}
+	if got := strings.Join(tags, ","); got != "1.0,1.1,1.2,latest" {
+		t.Errorf("got tags %s", got)
+	}
+}
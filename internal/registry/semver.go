@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is a tag parsed as a dotted numeric version such as "v1.25.3-alpine"
+type version struct {
+	prefix  string // "v" or ""
+	numbers []int
+	suffix  string // everything after the first "-", e.g. "alpine"
+}
+
+// parseVersion parses a tag with one to three numeric components
+func parseVersion(tag string) (version, bool) {
+	var v version
+	if strings.HasPrefix(tag, "v") {
+		v.prefix = "v"
+		tag = tag[1:]
+	}
+
+	core, suffix, _ := strings.Cut(tag, "-")
+	v.suffix = suffix
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return version{}, false
+	}
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.numbers = append(v.numbers, n)
+	}
+
+	return v, true
+}
+
+// comparable reports whether two versions share a tag scheme, so "1.25-alpine"
+// is only ever compared with other "x.y-alpine" tags
+func (v version) comparable(other version) bool {
+	return v.prefix == other.prefix && v.suffix == other.suffix && len(v.numbers) == len(other.numbers)
+}
+
+// less reports whether v precedes other
+func (v version) less(other version) bool {
+	for i := range v.numbers {
+		if v.numbers[i] != other.numbers[i] {
+			return v.numbers[i] < other.numbers[i]
+		}
+	}
+	return false
+}
+
+// NewestTag returns the highest tag following the same version scheme as
+// current, or "" when current is not a version or nothing newer exists
+func NewestTag(current string, tags []string) string {
+	base, ok := parseVersion(current)
+	if !ok {
+		return ""
+	}
+
+	newest, newestTag := base, ""
+	for _, tag := range tags {
+		candidate, ok := parseVersion(tag)
+		if !ok || !base.comparable(candidate) {
+			continue
+		}
+		if newest.less(candidate) {
+			newest, newestTag = candidate, tag
+		}
+	}
+
+	return newestTag
+}
@@ -0,0 +1,27 @@
+package registry
+
+import "testing"
+
+func TestNewestTag(t *testing.T) {
+	tags := []string{"latest", "1.24", "1.25", "1.25.3", "1.25.4", "1.26.0", "1.26.0-alpine", "1.27-alpine", "v2.0.0", "2.0.0-rc1", "nightly"}
+
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{"1.25.3", "1.26.0"},
+		{"1.25", ""}, // already the newest two-part tag
+		{"1.24", "1.25"},
+		{"1.26.0-alpine", ""},
+		{"1.25-alpine", "1.27-alpine"},
+		{"v1.0.0", "v2.0.0"},
+		{"latest", ""},
+		{"nightly", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NewestTag(tt.current, tags); got != tt.want {
+			t.Errorf("NewestTag(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package security
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Severity levels, from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// Rule describes a single posture check
+type Rule struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity"`
+	Remediation string `json:"remediation"`
+}
+
+// Rules lists every check performed by the audit, in report order
+var Rules = []Rule{
+	{ID: "GCO001", Title: "Privileged container", Severity: SeverityCritical,
+		Remediation: "Remove --privileged and grant only the specific capabilities or devices the workload needs."},
+	{ID: "GCO002", Title: "Docker socket mounted", Severity: SeverityCritical,
+		Remediation: "Do not mount /var/run/docker.sock; use a socket proxy limited to the required API calls."},
+	{ID: "GCO003", Title: "Dangerous capability added", Severity: SeverityHigh,
+		Remediation: "Drop the capability from cap_add, or replace it with a narrower one."},
+	{ID: "GCO004", Title: "Host network namespace", Severity: SeverityHigh,
+		Remediation: "Use a bridge or user-defined network and publish only the required ports."},
+	{ID: "GCO005", Title: "Host PID namespace", Severity: SeverityHigh,
+		Remediation: "Remove --pid=host so the container cannot see or signal host processes."},
+	{ID: "GCO006", Title: "Host IPC namespace", Severity: SeverityMedium,
+		Remediation: "Remove --ipc=host, or use shareable IPC between specific containers."},
+	{ID: "GCO007", Title: "Sensitive host path mounted", Severity: SeverityHigh,
+		Remediation: "Mount only the specific files needed, read-only, or use a named volume."},
+	{ID: "GCO008", Title: "Seccomp disabled", Severity: SeverityHigh,
+		Remediation: "Remove seccomp=unconfined; use the default profile or a custom one allowing the required syscalls."},
+	{ID: "GCO009", Title: "AppArmor disabled", Severity: SeverityMedium,
+		Remediation: "Remove apparmor=unconfined and run with the docker-default or a custom profile."},
+	{ID: "GCO010", Title: "Running as root", Severity: SeverityMedium,
+		Remediation: "Set USER in the image or --user to a non-root UID."},
+	{ID: "GCO011", Title: "No memory limit", Severity: SeverityMedium,
+		Remediation: "Set --memory (mem_limit in compose) so one container cannot exhaust host memory."},
+	{ID: "GCO012", Title: "No PIDs limit", Severity: SeverityLow,
+		Remediation: "Set --pids-limit to protect the host from fork bombs."},
+	{ID: "GCO013", Title: "Writable root filesystem", Severity: SeverityLow,
+		Remediation: "Run with --read-only and mount tmpfs or volumes for paths that need writes."},
+}
+
+// dangerousCapabilities grant near-root control over the host when added
+var dangerousCapabilities = map[string]bool{
+	"ALL":             true,
+	"SYS_ADMIN":       true,
+	"SYS_MODULE":      true,
+	"SYS_PTRACE":      true,
+	"SYS_RAWIO":       true,
+	"SYS_BOOT":        true,
+	"SYS_TIME":        true,
+	"NET_ADMIN":       true,
+	"DAC_READ_SEARCH": true,
+	"BPF":             true,
+	"PERFMON":         true,
+}
+
+// sensitiveHostPaths must not be bind-mounted from the host, nor may the host root itself
+var sensitiveHostPaths = []string{"/etc", "/root", "/proc", "/sys", "/boot", "/dev", "/var/lib/docker", "/run/containerd"}
+
+// Finding is a single rule violation on a container
+type Finding struct {
+	RuleID      string `json:"rule_id"`
+	Title       string `json:"title"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation"`
+}
+
+// ContainerAudit holds the findings for one container
+type ContainerAudit struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Image    string    `json:"image"`
+	Findings []Finding `json:"findings"`
+}
+
+// Report is the result of auditing a set of containers
+type Report struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Containers  []ContainerAudit `json:"containers"`
+	BySeverity  map[string]int   `json:"by_severity"`
+	ByRule      map[string]int   `json:"by_rule"`
+}
+
+// HostSecurity describes the security features enabled on the Docker daemon.
+// Host-wide checks only run when Known is set, i.e. the daemon reported its options.
+type HostSecurity struct {
+	Known    bool
+	AppArmor bool
+	Seccomp  bool
+}
+
+// ParseHostSecurity reads the security options reported by the daemon's info endpoint
+// (e.g. "name=apparmor", "name=seccomp,profile=builtin")
+func ParseHostSecurity(securityOptions []string) HostSecurity {
+	host := HostSecurity{Known: true}
+	for _, option := range securityOptions {
+		switch {
+		case strings.Contains(option, "name=apparmor"):
+			host.AppArmor = true
+		case strings.Contains(option, "name=seccomp"):
+			host.Seccomp = true
+		}
+	}
+	return host
+}
+
+// NewReport builds a report from per-container audits
+func NewReport(audits []ContainerAudit) Report {
+	report := Report{
+		GeneratedAt: time.Now(),
+		Containers:  audits,
+		BySeverity:  make(map[string]int),
+		ByRule:      make(map[string]int),
+	}
+
+	for _, audit := range audits {
+		for _, finding := range audit.Findings {
+			report.BySeverity[finding.Severity]++
+			report.ByRule[finding.RuleID]++
+		}
+	}
+
+	sort.Slice(report.Containers, func(i, j int) bool {
+		return report.Containers[i].Name < report.Containers[j].Name
+	})
+
+	return report
+}
+
+// AuditContainer checks a container's inspect data against every rule
+func AuditContainer(info types.ContainerJSON, host HostSecurity) ContainerAudit {
+	audit := ContainerAudit{Findings: []Finding{}}
+	if info.ContainerJSONBase == nil || info.HostConfig == nil {
+		return audit
+	}
+
+	audit.ID = info.ID
+	if len(audit.ID) > 12 {
+		audit.ID = audit.ID[:12]
+	}
+	audit.Name = strings.TrimPrefix(info.Name, "/")
+
+	hc := info.HostConfig
+	user := ""
+	if info.Config != nil {
+		audit.Image = info.Config.Image
+		user = info.Config.User
+	}
+
+	add := func(ruleID, message string) {
+		audit.Findings = append(audit.Findings, newFinding(ruleID, message))
+	}
+
+	if hc.Privileged {
+		add("GCO001", "Container runs with --privileged and has full access to host devices")
+	}
+
+	for _, m := range info.Mounts {
+		if m.Type != "bind" {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(m.Source, "/docker.sock"):
+			add("GCO002", "Docker socket "+m.Source+" is mounted at "+m.Destination)
+		case isSensitivePath(m.Source):
+			message := "Host path " + m.Source + " is mounted at " + m.Destination
+			if m.RW {
+				message += " (read-write)"
+			}
+			add("GCO007", message)
+		}
+	}
+
+	for _, capability := range hc.CapAdd {
+		name := strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+		if dangerousCapabilities[name] {
+			add("GCO003", "Capability "+name+" is added")
+		}
+	}
+
+	if hc.NetworkMode.IsHost() {
+		add("GCO004", "Container shares the host network namespace")
+	}
+	if hc.PidMode.IsHost() {
+		add("GCO005", "Container shares the host PID namespace")
+	}
+	if hc.IpcMode.IsHost() {
+		add("GCO006", "Container shares the host IPC namespace")
+	}
+
+	if !hc.Privileged {
+		if securityOptDisabled(hc.SecurityOpt, "seccomp") || (host.Known && !host.Seccomp) {
+			add("GCO008", "Container runs without a seccomp profile")
+		}
+		if securityOptDisabled(hc.SecurityOpt, "apparmor") || (host.Known && host.AppArmor && (info.AppArmorProfile == "" || info.AppArmorProfile == "unconfined")) {
+			add("GCO009", "Container runs without an AppArmor profile")
+		}
+	}
+
+	if isRootUser(user) {
+		add("GCO010", "Container processes run as root")
+	}
+	if hc.Memory == 0 {
+		add("GCO011", "No memory limit is set")
+	}
+	if hc.PidsLimit == nil || *hc.PidsLimit <= 0 {
+		add("GCO012", "No PIDs limit is set")
+	}
+	if !hc.ReadonlyRootfs {
+		add("GCO013", "Root filesystem is writable")
+	}
+
+	return audit
+}
+
+// newFinding fills a finding from its rule definition
+func newFinding(ruleID, message string) Finding {
+	for _, rule := range Rules {
+		if rule.ID == ruleID {
+			return Finding{
+				RuleID:      rule.ID,
+				Title:       rule.Title,
+				Severity:    rule.Severity,
+				Message:     message,
+				Remediation: rule.Remediation,
+			}
+		}
+	}
+	return Finding{RuleID: ruleID, Message: message}
+}
+
+// isSensitivePath reports whether a host path is, or lies inside, a sensitive location
+func isSensitivePath(path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		return true // the host root
+	}
+	for _, sensitive := range sensitiveHostPaths {
+		if path == sensitive || strings.HasPrefix(path, sensitive+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// securityOptDisabled reports whether a security option such as seccomp is set to unconfined
+func securityOptDisabled(options []string, name string) bool {
+	for _, option := range options {
+		if option == name+"=unconfined" || option == name+":unconfined" {
+			return true
+		}
+	}
+	return false
+}
+
+// isRootUser reports whether a Config.User value resolves to root
+func isRootUser(user string) bool {
+	name, _, _ := strings.Cut(user, ":")
+	return name == "" || name == "root" || name == "0"
+}
@@ -0,0 +1,129 @@
+package security
+
+// SARIF 2.1.0 output, so audit results can be uploaded to code scanning dashboards
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is the root object of a SARIF file
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun holds the results of a single tool run
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool and its rules
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the analysis tool
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a single check
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	ShortDescription SARIFMessage      `json:"shortDescription"`
+	Help             SARIFMessage      `json:"help"`
+	Properties       map[string]string `json:"properties"`
+}
+
+// SARIFResult is a single finding
+type SARIFResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    SARIFMessage      `json:"message"`
+	Locations  []SARIFLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+// SARIFMessage is a plain-text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation points at the audited container
+type SARIFLocation struct {
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+}
+
+// SARIFLogicalLocation identifies a container by name
+type SARIFLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ToSARIF converts an audit report into a SARIF log
+func (r Report) ToSARIF() SARIFLog {
+	rules := make([]SARIFRule, 0, len(Rules))
+	for _, rule := range Rules {
+		rules = append(rules, SARIFRule{
+			ID:               rule.ID,
+			Name:             rule.Title,
+			ShortDescription: SARIFMessage{Text: rule.Title},
+			Help:             SARIFMessage{Text: rule.Remediation},
+			Properties:       map[string]string{"severity": rule.Severity},
+		})
+	}
+
+	results := []SARIFResult{}
+	for _, audit := range r.Containers {
+		for _, finding := range audit.Findings {
+			results = append(results, SARIFResult{
+				RuleID:  finding.RuleID,
+				Level:   sarifLevel(finding.Severity),
+				Message: SARIFMessage{Text: audit.Name + ": " + finding.Message},
+				Locations: []SARIFLocation{{
+					LogicalLocations: []SARIFLogicalLocation{{
+						Name:               audit.Name,
+						FullyQualifiedName: audit.Image + "/" + audit.Name,
+						Kind:               "container",
+					}},
+				}},
+				Properties: map[string]string{
+					"container_id": audit.ID,
+					"image":        audit.Image,
+					"severity":     finding.Severity,
+				},
+			})
+		}
+	}
+
+	return SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "gocontainerops",
+				InformationURI: "https://github.com/enricoconvento98/gocontainerops",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// sarifLevel maps audit severities onto SARIF result levels
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,313 @@
+package security
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redaction modes for log output
+const (
+	RedactionOff     = "off"     // serve logs unchanged
+	RedactionMask    = "mask"    // replace the whole match with [REDACTED:<pattern>]
+	RedactionPartial = "partial" // keep the first four characters of the match
+)
+
+// SecretPattern is a named regular expression matching a kind of secret
+type SecretPattern struct {
+	Name  string
+	Regex *regexp.Regexp
+}
+
+// builtinSecretPatterns are always checked by the scanner
+var builtinSecretPatterns = []SecretPattern{
+	{Name: "aws_access_key_id", Regex: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "aws_secret_access_key", Regex: regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,10}?[=:"'\s]([0-9a-zA-Z/+]{40})\b`)},
+	{Name: "jwt", Regex: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	// The whole PEM block up to its END line, or the rest of the text when it is cut off
+	{Name: privateKeyName, Regex: regexp.MustCompile(pemBegin.String() + `(?:[\s\S]*?` + pemEnd.String() + `|[\s\S]*)`)},
+}
+
+// PEM private key armor. Log lines arrive one frame at a time, so LogRedactor
+// tracks blocks spanning several frames with these.
+var (
+	pemBegin       = regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)
+	pemEnd         = regexp.MustCompile(`-----END (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)
+	privateKeyName = "private_key"
+)
+
+// High-entropy detection: long tokens mixing upper case, lower case and digits whose
+// Shannon entropy exceeds what hex digests (at most 4 bits per character) can reach.
+// Slashes are left out of the alphabet so URL paths are not mistaken for tokens.
+var (
+	entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+_-]{24,}={0,2}`)
+	minSecretEntropy = 4.3
+	highEntropyName  = "high_entropy_string"
+)
+
+// SecretMatch is a single secret found in a piece of text
+type SecretMatch struct {
+	Pattern string `json:"pattern"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+}
+
+// SecretFinding is a secret found in a container's environment or logs
+type SecretFinding struct {
+	Source   string `json:"source"` // "env" or "logs"
+	Pattern  string `json:"pattern"`
+	Variable string `json:"variable,omitempty"` // environment variable name
+	Line     int    `json:"line,omitempty"`     // 1-based line in the scanned log tail
+	Preview  string `json:"preview"`            // redacted excerpt
+}
+
+// ContainerSecrets holds the secret findings for one container
+type ContainerSecrets struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Image    string          `json:"image"`
+	Findings []SecretFinding `json:"findings"`
+}
+
+// SecretScanner detects and redacts secrets using built-in and custom patterns
+type SecretScanner struct {
+	patterns      []SecretPattern
+	redactionMode string
+}
+
+// NewSecretScanner creates a scanner. Custom patterns are "name=regex" entries.
+func NewSecretScanner(customPatterns []string, redactionMode string) (*SecretScanner, error) {
+	switch redactionMode {
+	case "":
+		redactionMode = RedactionMask
+	case RedactionOff, RedactionMask, RedactionPartial:
+	default:
+		return nil, fmt.Errorf("unknown redaction mode %q (use off, mask or partial)", redactionMode)
+	}
+
+	patterns := append([]SecretPattern{}, builtinSecretPatterns...)
+	for _, custom := range customPatterns {
+		name, expr, found := strings.Cut(custom, "=")
+		if !found || name == "" || expr == "" {
+			return nil, fmt.Errorf("invalid secret pattern %q (expected name=regex)", custom)
+		}
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %q: %v", name, err)
+		}
+		patterns = append(patterns, SecretPattern{Name: name, Regex: regex})
+	}
+
+	return &SecretScanner{patterns: patterns, redactionMode: redactionMode}, nil
+}
+
+// RedactionMode returns the configured log redaction mode
+func (s *SecretScanner) RedactionMode() string {
+	return s.redactionMode
+}
+
+// Scan returns the non-overlapping secrets found in text, in order of appearance
+func (s *SecretScanner) Scan(text string) []SecretMatch {
+	var matches []SecretMatch
+
+	for _, pattern := range s.patterns {
+		for _, loc := range pattern.Regex.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			// Patterns with a capture group only flag the captured part
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			matches = append(matches, SecretMatch{Pattern: pattern.Name, Start: start, End: end})
+		}
+	}
+
+	for _, loc := range entropyCandidate.FindAllStringIndex(text, -1) {
+		token := text[loc[0]:loc[1]]
+		if mixedCharacterClasses(token) && shannonEntropy(token) >= minSecretEntropy {
+			matches = append(matches, SecretMatch{Pattern: highEntropyName, Start: loc[0], End: loc[1]})
+		}
+	}
+
+	// Keep the earliest match at each position and drop overlaps
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	var result []SecretMatch
+	lastEnd := -1
+	for _, match := range matches {
+		if match.Start >= lastEnd {
+			result = append(result, match)
+			lastEnd = match.End
+		}
+	}
+
+	return result
+}
+
+// Redact replaces the secrets in text according to the configured mode
+func (s *SecretScanner) Redact(text string) string {
+	return s.RedactWith(text, s.redactionMode)
+}
+
+// RedactWith replaces the secrets in text using the given mode
+func (s *SecretScanner) RedactWith(text, mode string) string {
+	if mode == RedactionOff {
+		return text
+	}
+
+	matches := s.Scan(text)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(text[last:match.Start])
+		b.WriteString(redactMatch(text[match.Start:match.End], match.Pattern, mode))
+		last = match.End
+	}
+	b.WriteString(text[last:])
+
+	return b.String()
+}
+
+// LogRedactor redacts a stream of log frames, carrying open PEM blocks from one
+// frame to the next so every line of a private key is masked
+type LogRedactor struct {
+	scanner *SecretScanner
+	mode    string
+	inKey   bool
+}
+
+// NewLogRedactor creates a redactor for one log stream using the given mode
+func (s *SecretScanner) NewLogRedactor(mode string) *LogRedactor {
+	return &LogRedactor{scanner: s, mode: mode}
+}
+
+// Redact replaces the secrets in the next frame of the stream. The BEGIN line of a
+// private key is replaced like any other match; the lines up to and including the
+// END line are emptied, keeping their line breaks.
+func (r *LogRedactor) Redact(text string) string {
+	if r.mode == RedactionOff {
+		return text
+	}
+
+	var b strings.Builder
+	rest := text
+	for rest != "" {
+		if r.inKey {
+			loc := pemEnd.FindStringIndex(rest)
+			if loc == nil {
+				b.WriteString(strings.Repeat("\n", strings.Count(rest, "\n")))
+				break
+			}
+			b.WriteString(strings.Repeat("\n", strings.Count(rest[:loc[1]], "\n")))
+			rest = rest[loc[1]:]
+			r.inKey = false
+			continue
+		}
+
+		loc := pemBegin.FindStringIndex(rest)
+		if loc == nil {
+			b.WriteString(r.scanner.RedactWith(rest, r.mode))
+			break
+		}
+		b.WriteString(r.scanner.RedactWith(rest[:loc[0]], r.mode))
+		b.WriteString(redactMatch(rest[loc[0]:loc[1]], privateKeyName, r.mode))
+		rest = rest[loc[1]:]
+		r.inKey = true
+	}
+
+	return b.String()
+}
+
+// ScanEnv checks KEY=VALUE entries and reports the variables holding secrets
+func (s *SecretScanner) ScanEnv(env []string) []SecretFinding {
+	var findings []SecretFinding
+	for _, entry := range env {
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		for _, match := range s.Scan(value) {
+			findings = append(findings, SecretFinding{
+				Source:   "env",
+				Pattern:  match.Pattern,
+				Variable: key,
+				Preview:  key + "=" + s.RedactWith(value, RedactionPartial),
+			})
+		}
+	}
+	return findings
+}
+
+// ScanLines checks log output line by line
+func (s *SecretScanner) ScanLines(text string) []SecretFinding {
+	var findings []SecretFinding
+	for i, line := range strings.Split(text, "\n") {
+		for _, match := range s.Scan(line) {
+			findings = append(findings, SecretFinding{
+				Source:  "logs",
+				Pattern: match.Pattern,
+				Line:    i + 1,
+				Preview: truncate(s.RedactWith(line, RedactionPartial), 200),
+			})
+		}
+	}
+	return findings
+}
+
+// redactMatch renders the replacement for a single secret
+func redactMatch(secret, pattern, mode string) string {
+	if mode == RedactionPartial && len(secret) > 8 {
+		return secret[:4] + strings.Repeat("*", 8)
+	}
+	return "[REDACTED:" + pattern + "]"
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, ch := range s {
+		counts[ch]++
+	}
+
+	entropy := 0.0
+	length := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// mixedCharacterClasses reports whether s contains upper case, lower case and digits
+func mixedCharacterClasses(s string) bool {
+	var upper, lower, digit bool
+	for _, ch := range s {
+		switch {
+		case ch >= 'A' && ch <= 'Z':
+			upper = true
+		case ch >= 'a' && ch <= 'z':
+			lower = true
+		case ch >= '0' && ch <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,114 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+// testPrivateKey returns a freshly generated RSA key in PEM form
+func testPrivateKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+}
+
+// keyBody returns the base64 lines between the BEGIN and END lines
+func keyBody(block string) []string {
+	lines := strings.Split(strings.TrimSpace(block), "\n")
+	return lines[1 : len(lines)-1]
+}
+
+func TestSecretScanner_RedactPEMBlock(t *testing.T) {
+	scanner, err := NewSecretScanner(nil, RedactionMask)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := testPrivateKey(t)
+	redacted := scanner.Redact("loading key\n" + block + "key loaded\n")
+
+	for _, line := range keyBody(block) {
+		if strings.Contains(redacted, line) {
+			t.Fatalf("key line %q left in output:\n%s", line, redacted)
+		}
+	}
+	if strings.Contains(redacted, "-----END") {
+		t.Errorf("END line left in output:\n%s", redacted)
+	}
+	if !strings.Contains(redacted, "[REDACTED:private_key]") || !strings.HasPrefix(redacted, "loading key\n") || !strings.HasSuffix(redacted, "key loaded\n") {
+		t.Errorf("unexpected output:\n%s", redacted)
+	}
+}
+
+func TestSecretScanner_RedactTruncatedPEMBlock(t *testing.T) {
+	scanner, err := NewSecretScanner(nil, RedactionMask)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := testPrivateKey(t)
+	truncated := block[:len(block)/2]
+	redacted := scanner.Redact(truncated)
+
+	if redacted != "[REDACTED:private_key]" {
+		t.Errorf("truncated block not fully redacted:\n%s", redacted)
+	}
+}
+
+func TestLogRedactor_PEMBlockAcrossFrames(t *testing.T) {
+	scanner, err := NewSecretScanner(nil, RedactionMask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redactor := scanner.NewLogRedactor(RedactionMask)
+
+	// Docker delivers one frame per line, each with a timestamp
+	block := testPrivateKey(t)
+	lines := append([]string{"starting"}, strings.Split(strings.TrimSpace(block), "\n")...)
+	lines = append(lines, "ready")
+
+	var out []string
+	for _, line := range lines {
+		out = append(out, redactor.Redact("2024-05-01T10:00:00.000000000Z "+line+"\n"))
+	}
+	output := strings.Join(out, "")
+
+	for _, line := range keyBody(block) {
+		if strings.Contains(output, line) {
+			t.Fatalf("key line %q left in output:\n%s", line, output)
+		}
+	}
+	if strings.Count(output, "\n") != len(lines) {
+		t.Errorf("expected %d lines, got %d", len(lines), strings.Count(output, "\n"))
+	}
+	if !strings.Contains(out[0], "starting") || !strings.Contains(out[len(out)-1], "ready") {
+		t.Errorf("lines around the key were not kept:\n%s", output)
+	}
+	if !strings.Contains(out[1], "[REDACTED:private_key]") {
+		t.Errorf("BEGIN line not redacted: %q", out[1])
+	}
+}
+
+func TestLogRedactor_Off(t *testing.T) {
+	scanner, err := NewSecretScanner(nil, RedactionMask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redactor := scanner.NewLogRedactor(RedactionOff)
+
+	block := testPrivateKey(t)
+	if got := redactor.Redact(block); got != block {
+		t.Errorf("redaction off changed the frame")
+	}
+}
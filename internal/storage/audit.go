@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"time"
+)
+
+// AuditEntry records an operation that changed state on the Docker host
+type AuditEntry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Action    string            `json:"action"` // e.g. "prune"
+	Target    string            `json:"target"` // e.g. "images", a container ID
+	Role      string            `json:"role"`
+	Remote    string            `json:"remote"` // client address
+	DryRun    bool              `json:"dry_run"`
+	Details   map[string]string `json:"details,omitempty"`
+	Items     []string          `json:"items,omitempty"`
+	Bytes     int64             `json:"bytes,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// AddAudit adds an audit entry to the store
+func (s *InMemoryStore) AddAudit(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.audit = append(s.audit, entry)
+
+	// Keep only last 1000 entries to prevent memory bloat
+	if len(s.audit) > 1000 {
+		s.audit = s.audit[len(s.audit)-1000:]
+	}
+
+	return nil
+}
+
+// GetAuditLog retrieves the most recent audit entries, newest first
+func (s *InMemoryStore) GetAuditLog(limit int) ([]AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []AuditEntry
+	for i := len(s.audit) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, s.audit[i])
+	}
+
+	return result, nil
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// availabilityRetention is how long state transitions are kept for availability reports
+const availabilityRetention = 31 * 24 * time.Hour
+
+// stateTransition records a container going up (start, restart) or down (stop).
+// Planned downs were requested through Docker and are not outages.
+type stateTransition struct {
+	Timestamp time.Time
+	Up        bool
+	Planned   bool
+}
+
+// ContainerAvailability holds availability statistics of a container over a window
+type ContainerAvailability struct {
+	ContainerID     string    `json:"container_id"`
+	ContainerName   string    `json:"container_name"`
+	Since           time.Time `json:"since"`
+	Until           time.Time `json:"until"`
+	ObservedSeconds float64   `json:"observed_seconds"` // part of the window with a known state, less planned downtime
+	UptimeSeconds   float64   `json:"uptime_seconds"`
+	DowntimeSeconds float64   `json:"downtime_seconds"`
+	UptimePercent   float64   `json:"uptime_percent"`
+	Outages         int       `json:"outages"`
+	MTBFSeconds     float64   `json:"mtbf_seconds"` // mean uptime between outages
+	MTTRSeconds     float64   `json:"mttr_seconds"` // mean outage duration
+	LongestOutage   float64   `json:"longest_outage_seconds"`
+	PlannedStops    int       `json:"planned_stops"`
+	PlannedSeconds  float64   `json:"planned_downtime_seconds"`
+	Up              bool      `json:"up"` // state at the end of the window
+}
+
+// recordTransition tracks start, restart and stop events for availability. Stops
+// marked as requested (docker stop or kill) are planned. Callers must hold s.mu.
+func (s *InMemoryStore) recordTransition(event ContainerEvent) {
+	transition := stateTransition{Timestamp: event.Timestamp}
+	switch event.EventType {
+	case "start", "restart":
+		transition.Up = true
+	case "stop":
+		transition.Planned = event.Details["requested"] == "true"
+	default:
+		return
+	}
+
+	transitions := append(s.transitions[event.ContainerID], transition)
+
+	// Drop transitions older than the retention, keeping the last one before the
+	// cutoff so the state at the start of the oldest window stays known
+	cutoff := event.Timestamp.Add(-availabilityRetention)
+	drop := 0
+	for drop+1 < len(transitions) && transitions[drop+1].Timestamp.Before(cutoff) {
+		drop++
+	}
+	s.transitions[event.ContainerID] = transitions[drop:]
+	s.containerNames[event.ContainerID] = event.ContainerName
+}
+
+// SeedTransitions records the last known transitions of a container that existed
+// before monitoring started: up at startedAt and, unless it is still running, down
+// at finishedAt. Docker does not record why a container stopped, so a seeded stop
+// counts as an outage. Nothing is recorded once the container has transitions, so seeding
+// again after a reconnect is harmless. No events are emitted.
+func (s *InMemoryStore) SeedTransitions(containerID, containerName string, startedAt, finishedAt time.Time, running bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.transitions[containerID]) > 0 || startedAt.IsZero() {
+		return nil
+	}
+
+	transitions := []stateTransition{{Timestamp: startedAt, Up: true}}
+	if !running && finishedAt.After(startedAt) {
+		transitions = append(transitions, stateTransition{Timestamp: finishedAt, Up: false})
+	}
+	s.transitions[containerID] = transitions
+	s.containerNames[containerID] = containerName
+
+	return nil
+}
+
+// GetAvailability computes availability over a time range for every container with recorded transitions
+func (s *InMemoryStore) GetAvailability(since, until time.Time) ([]ContainerAvailability, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]ContainerAvailability, 0, len(s.transitions))
+	for id, transitions := range s.transitions {
+		availability, ok := computeAvailability(transitions, since, until)
+		if !ok {
+			continue
+		}
+		availability.ContainerID = id
+		availability.ContainerName = s.containerNames[id]
+		result = append(result, availability)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UptimePercent != result[j].UptimePercent {
+			return result[i].UptimePercent < result[j].UptimePercent
+		}
+		return result[i].ContainerName < result[j].ContainerName
+	})
+
+	return result, nil
+}
+
+// computeAvailability walks a container's transitions across a window. The state
+// before the first transition is unknown, so observation starts there when it
+// falls inside the window. Planned downtime is reported on its own and left out
+// of the observed time, so it neither counts as an outage nor lowers the uptime.
+func computeAvailability(transitions []stateTransition, since, until time.Time) (ContainerAvailability, bool) {
+	availability := ContainerAvailability{Since: since, Until: until}
+	if len(transitions) == 0 || !transitions[0].Timestamp.Before(until) {
+		return availability, false
+	}
+
+	// The state at the start of the window comes from the last transition before it
+	var known, up, planned bool
+	i := 0
+	for ; i < len(transitions) && !transitions[i].Timestamp.After(since); i++ {
+		known, up, planned = true, transitions[i].Up, transitions[i].Planned
+	}
+
+	cursor := since
+	var outageStart time.Time
+	if known && !up && !planned {
+		availability.Outages++
+		outageStart = since
+	}
+
+	closeSpan := func(end time.Time) {
+		if !known || !end.After(cursor) {
+			return
+		}
+		seconds := end.Sub(cursor).Seconds()
+		switch {
+		case up:
+			availability.ObservedSeconds += seconds
+			availability.UptimeSeconds += seconds
+		case planned:
+			availability.PlannedSeconds += seconds
+		default:
+			availability.ObservedSeconds += seconds
+			availability.DowntimeSeconds += seconds
+		}
+	}
+
+	for ; i < len(transitions) && !transitions[i].Timestamp.After(until); i++ {
+		t := transitions[i]
+		closeSpan(t.Timestamp)
+		cursor = t.Timestamp
+
+		if t.Up && !outageStart.IsZero() {
+			availability.LongestOutage = max(availability.LongestOutage, t.Timestamp.Sub(outageStart).Seconds())
+			outageStart = time.Time{}
+		}
+		if !t.Up && (!known || up) {
+			if t.Planned {
+				availability.PlannedStops++
+			} else {
+				availability.Outages++
+				outageStart = t.Timestamp
+			}
+		}
+		known, up, planned = true, t.Up, t.Planned
+	}
+
+	closeSpan(until)
+	if !outageStart.IsZero() {
+		// An outage still in progress at the end of the window
+		availability.LongestOutage = max(availability.LongestOutage, until.Sub(outageStart).Seconds())
+	}
+	if availability.ObservedSeconds == 0 && availability.PlannedSeconds == 0 {
+		return availability, false
+	}
+
+	availability.Up = up
+	availability.UptimePercent = 100.0 // stopped on purpose for the whole window
+	if availability.ObservedSeconds > 0 {
+		availability.UptimePercent = availability.UptimeSeconds / availability.ObservedSeconds * 100.0
+	}
+	if availability.Outages > 0 {
+		availability.MTBFSeconds = availability.UptimeSeconds / float64(availability.Outages)
+		availability.MTTRSeconds = availability.DowntimeSeconds / float64(availability.Outages)
+	}
+
+	return availability, true
+}
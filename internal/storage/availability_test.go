@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeAvailability(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	until := since.Add(100 * time.Second)
+	at := func(seconds int) time.Time { return since.Add(time.Duration(seconds) * time.Second) }
+	up := func(seconds int) stateTransition { return stateTransition{Timestamp: at(seconds), Up: true} }
+	down := func(seconds int) stateTransition { return stateTransition{Timestamp: at(seconds)} }
+	stop := func(seconds int) stateTransition { return stateTransition{Timestamp: at(seconds), Planned: true} }
+
+	tests := []struct {
+		name        string
+		transitions []stateTransition
+		wantOK      bool
+		want        ContainerAvailability
+	}{
+		{name: "no transitions"},
+		{name: "first transition after the window", transitions: []stateTransition{up(100)}},
+		{
+			name:        "up for the whole window",
+			transitions: []stateTransition{up(-10)},
+			wantOK:      true,
+			want:        ContainerAvailability{ObservedSeconds: 100, UptimeSeconds: 100, UptimePercent: 100, Up: true},
+		},
+		{
+			name:        "state unknown before the first transition",
+			transitions: []stateTransition{up(40)},
+			wantOK:      true,
+			want:        ContainerAvailability{ObservedSeconds: 60, UptimeSeconds: 60, UptimePercent: 100, Up: true},
+		},
+		{
+			name:        "outage",
+			transitions: []stateTransition{up(-10), down(20), up(50)},
+			wantOK:      true,
+			want: ContainerAvailability{
+				ObservedSeconds: 100, UptimeSeconds: 70, DowntimeSeconds: 30, UptimePercent: 70,
+				Outages: 1, MTBFSeconds: 70, MTTRSeconds: 30, LongestOutage: 30, Up: true,
+			},
+		},
+		{
+			name:        "outage in progress at the window end",
+			transitions: []stateTransition{up(-10), down(80)},
+			wantOK:      true,
+			want: ContainerAvailability{
+				ObservedSeconds: 100, UptimeSeconds: 80, DowntimeSeconds: 20, UptimePercent: 80,
+				Outages: 1, MTBFSeconds: 80, MTTRSeconds: 20, LongestOutage: 20,
+			},
+		},
+		{
+			name:        "down at the window start",
+			transitions: []stateTransition{down(0), up(30)},
+			wantOK:      true,
+			want: ContainerAvailability{
+				ObservedSeconds: 100, UptimeSeconds: 70, DowntimeSeconds: 30, UptimePercent: 70,
+				Outages: 1, MTBFSeconds: 70, MTTRSeconds: 30, LongestOutage: 30, Up: true,
+			},
+		},
+		{
+			name:        "planned stop",
+			transitions: []stateTransition{up(-10), stop(20), up(50)},
+			wantOK:      true,
+			want: ContainerAvailability{
+				ObservedSeconds: 70, UptimeSeconds: 70, UptimePercent: 100,
+				PlannedStops: 1, PlannedSeconds: 30, Up: true,
+			},
+		},
+		{
+			name:        "stopped on purpose for the whole window",
+			transitions: []stateTransition{up(-20), stop(-10)},
+			wantOK:      true,
+			want:        ContainerAvailability{UptimePercent: 100, PlannedSeconds: 100},
+		},
+		{
+			name:        "transitions after the window are ignored",
+			transitions: []stateTransition{up(-10), down(150)},
+			wantOK:      true,
+			want:        ContainerAvailability{ObservedSeconds: 100, UptimeSeconds: 100, UptimePercent: 100, Up: true},
+		},
+	}
+
+	for _, tt := range tests {
+		got, ok := computeAvailability(tt.transitions, since, until)
+		if ok != tt.wantOK {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		tt.want.Since, tt.want.Until = since, until
+		if got != tt.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetAvailability_SeededAndRequestedStops(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewInMemoryStore()
+
+	s.SeedTransitions("web", "web", now.Add(-time.Hour), time.Time{}, true)
+	s.AddEvent(ContainerEvent{ContainerID: "web", ContainerName: "web", EventType: "stop", Timestamp: now.Add(-30 * time.Minute), Details: map[string]string{"requested": "true"}})
+	s.AddEvent(ContainerEvent{ContainerID: "web", ContainerName: "web", EventType: "start", Timestamp: now.Add(-20 * time.Minute)})
+
+	// Seeded stops count as outages, since Docker does not record why they happened
+	s.SeedTransitions("db", "db", now.Add(-time.Hour), now.Add(-15*time.Minute), false)
+	// Seeding again once transitions exist is a no-op
+	s.SeedTransitions("db", "db", now.Add(-time.Hour), time.Time{}, true)
+
+	result, err := s.GetAvailability(now.Add(-time.Hour), now)
+	if err != nil || len(result) != 2 {
+		t.Fatalf("GetAvailability() = %+v, %v", result, err)
+	}
+
+	db, web := result[0], result[1]
+	if db.ContainerName != "db" || db.Outages != 1 || db.UptimePercent != 75 || db.Up {
+		t.Errorf("db = %+v", db)
+	}
+	if web.ContainerName != "web" || web.Outages != 0 || web.PlannedStops != 1 || web.PlannedSeconds != 600 || web.UptimePercent != 100 {
+		t.Errorf("web = %+v", web)
+	}
+}
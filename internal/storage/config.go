@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ConfigSnapshot represents a container's configuration at the time it started
+type ConfigSnapshot struct {
+	Key           string            `json:"key"` // compose "project/service" or container name
+	Version       int               `json:"version"`
+	ContainerID   string            `json:"container_id"`
+	ContainerName string            `json:"container_name"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Image         string            `json:"image"`
+	ImageID       string            `json:"image_id"` // content digest of the image config
+	Command       []string          `json:"command"`
+	Entrypoint    []string          `json:"entrypoint"`
+	Env           []string          `json:"env"`
+	Mounts        []string          `json:"mounts"` // "source:destination:mode"
+	Labels        map[string]string `json:"labels"`
+	Limits        map[string]string `json:"limits"`
+	Changes       []ConfigChange    `json:"changes,omitempty"`
+}
+
+// ConfigChange describes a single field that differs between two snapshots
+type ConfigChange struct {
+	Field string `json:"field"` // e.g. "image_id", "env.LOG_LEVEL", "labels.team", "limits.memory"
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// AddConfigSnapshot diffs a snapshot against the latest version for the same key and
+// stores it as a new version when anything changed. It returns the latest version
+// and whether a new one was stored.
+func (s *InMemoryStore) AddConfigSnapshot(snapshot ConfigSnapshot) (ConfigSnapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.configHistory[snapshot.Key]
+	if len(history) > 0 {
+		previous := history[len(history)-1]
+		changes := DiffConfigSnapshots(previous, snapshot)
+		if len(changes) == 0 {
+			return previous, false, nil
+		}
+		snapshot.Changes = changes
+		snapshot.Version = previous.Version + 1
+	} else {
+		snapshot.Changes = nil
+		snapshot.Version = 1
+	}
+
+	history = append(history, snapshot)
+
+	// Keep only the last 50 versions per key
+	if len(history) > 50 {
+		history = history[len(history)-50:]
+	}
+	s.configHistory[snapshot.Key] = history
+
+	return snapshot, true, nil
+}
+
+// GetConfigHistory retrieves all stored versions for a key, oldest first
+func (s *InMemoryStore) GetConfigHistory(key string) ([]ConfigSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.configHistory[key]
+	result := make([]ConfigSnapshot, len(history))
+	copy(result, history)
+
+	return result, nil
+}
+
+// DiffConfigSnapshots lists the fields that differ between two snapshots
+func DiffConfigSnapshots(previous, next ConfigSnapshot) []ConfigChange {
+	var changes []ConfigChange
+
+	if previous.Image != next.Image {
+		changes = append(changes, ConfigChange{Field: "image", Old: previous.Image, New: next.Image})
+	}
+	if previous.ImageID != next.ImageID {
+		changes = append(changes, ConfigChange{Field: "image_id", Old: previous.ImageID, New: next.ImageID})
+	}
+	if joined(previous.Command) != joined(next.Command) {
+		changes = append(changes, ConfigChange{Field: "command", Old: joined(previous.Command), New: joined(next.Command)})
+	}
+	if joined(previous.Entrypoint) != joined(next.Entrypoint) {
+		changes = append(changes, ConfigChange{Field: "entrypoint", Old: joined(previous.Entrypoint), New: joined(next.Entrypoint)})
+	}
+
+	changes = append(changes, diffMaps("env", envMap(previous.Env), envMap(next.Env))...)
+	changes = append(changes, diffMaps("mounts", mountMap(previous.Mounts), mountMap(next.Mounts))...)
+	changes = append(changes, diffMaps("labels", previous.Labels, next.Labels)...)
+	changes = append(changes, diffMaps("limits", previous.Limits, next.Limits)...)
+
+	return changes
+}
+
+// diffMaps compares two maps key by key, prefixing each field with the given name
+func diffMaps(prefix string, previous, next map[string]string) []ConfigChange {
+	keys := make(map[string]bool)
+	for k := range previous {
+		keys[k] = true
+	}
+	for k := range next {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []ConfigChange
+	for _, k := range sorted {
+		oldValue, inOld := previous[k]
+		newValue, inNew := next[k]
+		if inOld == inNew && oldValue == newValue {
+			continue
+		}
+		changes = append(changes, ConfigChange{
+			Field: fmt.Sprintf("%s.%s", prefix, k),
+			Old:   oldValue,
+			New:   newValue,
+		})
+	}
+
+	return changes
+}
+
+// envMap converts KEY=VALUE entries into a map
+func envMap(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		result[key] = value
+	}
+	return result
+}
+
+// mountMap converts "source:destination:mode" entries into a map keyed on the destination
+func mountMap(mounts []string) map[string]string {
+	result := make(map[string]string, len(mounts))
+	for _, mount := range mounts {
+		key := mount
+		if parts := strings.Split(mount, ":"); len(parts) >= 2 {
+			key = parts[1]
+		}
+		result[key] = mount
+	}
+	return result
+}
+
+// joined renders a command line for comparison and display
+func joined(args []string) string {
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"time"
+)
+
+// DiskUsageSnapshot records Docker's disk usage at a point in time
+type DiskUsageSnapshot struct {
+	Timestamp        time.Time         `json:"timestamp"`
+	Images           DiskUsageCategory `json:"images"`
+	Containers       DiskUsageCategory `json:"containers"`
+	Volumes          DiskUsageCategory `json:"volumes"`
+	BuildCache       DiskUsageCategory `json:"build_cache"`
+	TotalSize        int64             `json:"total_size"`        // bytes
+	TotalReclaimable int64             `json:"total_reclaimable"` // bytes
+}
+
+// DiskUsageCategory holds the disk usage of one kind of Docker object
+type DiskUsageCategory struct {
+	Count       int   `json:"count"`
+	Active      int   `json:"active"`
+	Size        int64 `json:"size"`        // bytes
+	Reclaimable int64 `json:"reclaimable"` // bytes
+}
+
+// AddDiskUsage adds a disk usage snapshot to the store
+func (s *InMemoryStore) AddDiskUsage(snapshot DiskUsageSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.diskUsage = append(s.diskUsage, snapshot)
+
+	// Keep only the last 1000 snapshots (about 10 days at the default interval)
+	if len(s.diskUsage) > 1000 {
+		s.diskUsage = s.diskUsage[len(s.diskUsage)-1000:]
+	}
+
+	return nil
+}
+
+// GetDiskUsageHistory retrieves disk usage snapshots within a time range, oldest first
+func (s *InMemoryStore) GetDiskUsageHistory(since, until time.Time) ([]DiskUsageSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []DiskUsageSnapshot
+	for _, snapshot := range s.diskUsage {
+		if inRange(snapshot.Timestamp, since, until) {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+}
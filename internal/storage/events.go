@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"slices"
+	"time"
+)
+
+// eventRetention is how many events of each retention class are kept
+const eventRetention = 1000
+
+// highVolumeEventTypes are recorded often enough to crowd lifecycle events such as
+// restarts out of a shared buffer, so they are retained separately
+var highVolumeEventTypes = map[string]bool{
+	"health_status":     true,
+	"anomaly":           true,
+	"anomaly_resolved":  true,
+	"contention":        true,
+	"forecast_alert":    true,
+	"forecast_resolved": true,
+}
+
+// EventQuery filters container events. Zero values match everything.
+type EventQuery struct {
+	Container string   // container ID or name
+	IDs       []string // container IDs, e.g. those matching a label selector; nil matches all
+	Types     []string // event types, e.g. "restart", "stop"
+	Since     time.Time
+	Until     time.Time
+	Before    uint64 // only events with a lower ID; used as the pagination cursor
+	After     uint64 // only events with a higher ID; used to resume a stream
+	Limit     int    // 0 means no limit
+}
+
+// Matches reports whether an event satisfies the query's filters, ignoring Limit
+func (q EventQuery) Matches(event ContainerEvent) bool {
+	if q.Container != "" && event.ContainerID != q.Container && event.ContainerName != q.Container {
+		return false
+	}
+	if q.IDs != nil && !slices.Contains(q.IDs, event.ContainerID) {
+		return false
+	}
+	if len(q.Types) > 0 {
+		found := false
+		for _, t := range q.Types {
+			if event.EventType == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !q.Since.IsZero() && event.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && event.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Before > 0 && event.ID >= q.Before {
+		return false
+	}
+	if q.After > 0 && event.ID <= q.After {
+		return false
+	}
+	return true
+}
+
+// QueryEvents retrieves events matching a query, newest first
+func (s *InMemoryStore) QueryEvents(query EventQuery) ([]ContainerEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []ContainerEvent
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		if query.Matches(s.events[i]) {
+			result = append(result, s.events[i])
+		}
+	}
+
+	return result, nil
+}
+
+// SubscribeEvents delivers every event added after the call on the returned channel.
+// A subscriber that lets buffer events pile up has its channel closed so it can
+// resume from the store; the returned function unsubscribes.
+func (s *InMemoryStore) SubscribeEvents(buffer int) (<-chan ContainerEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan ContainerEvent, buffer)
+	s.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, exists := s.subscribers[ch]; exists {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publishEvent hands an event to every subscriber without blocking. Callers must hold s.mu.
+func (s *InMemoryStore) publishEvent(event ContainerEvent) {
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// EventsEvicted reports whether an event with an ID above after, and of one of the
+// given types (any type when empty), has been dropped by retention
+func (s *InMemoryStore) EventsEvicted(after uint64, types []string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(types) == 0 {
+		for _, id := range s.evictedEvents {
+			if id > after {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	for _, t := range types {
+		if s.evictedEvents[t] > after {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// trimEvents drops the oldest event of the same retention class as eventType once
+// that class holds more than eventRetention events. Callers must hold s.mu.
+func (s *InMemoryStore) trimEvents(eventType string) {
+	highVolume := highVolumeEventTypes[eventType]
+
+	count, oldest := 0, -1
+	for i, event := range s.events {
+		if highVolumeEventTypes[event.EventType] == highVolume {
+			if oldest < 0 {
+				oldest = i
+			}
+			count++
+		}
+	}
+	if count <= eventRetention {
+		return
+	}
+
+	evicted := s.events[oldest]
+	s.evictedEvents[evicted.EventType] = evicted.ID
+	s.events = append(s.events[:oldest], s.events[oldest+1:]...)
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// ExitRecord represents a classified container death
+type ExitRecord struct {
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	Image         string    `json:"image"`
+	Timestamp     time.Time `json:"timestamp"`
+	ExitCode      int       `json:"exit_code"`
+	Reason        string    `json:"reason"` // "oom_killed", "signal", "app_error", "clean_exit", "daemon_restart"
+	Signal        string    `json:"signal,omitempty"`
+	Requested     bool      `json:"requested"` // stopped or killed through Docker
+	Crash         bool      `json:"crash"`
+}
+
+// ExitAnalytics summarizes container deaths over a time range
+type ExitAnalytics struct {
+	Since        time.Time      `json:"since"`
+	Until        time.Time      `json:"until"`
+	TotalExits   int            `json:"total_exits"`
+	TotalCrashes int            `json:"total_crashes"`
+	ByReason     map[string]int `json:"by_reason"`
+	ByContainer  []ExitCount    `json:"by_container"`
+	ByImage      []ExitCount    `json:"by_image"`
+}
+
+// ExitCount holds exit counts for a single container or image
+type ExitCount struct {
+	Key      string         `json:"key"`
+	Name     string         `json:"name,omitempty"`
+	Exits    int            `json:"exits"`
+	Crashes  int            `json:"crashes"`
+	ByReason map[string]int `json:"by_reason"`
+	LastExit time.Time      `json:"last_exit"`
+}
+
+// AddExit adds a classified exit to the store
+func (s *InMemoryStore) AddExit(exit ExitRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exits = append(s.exits, exit)
+
+	// Keep only last 1000 exits to prevent memory bloat
+	if len(s.exits) > 1000 {
+		s.exits = s.exits[len(s.exits)-1000:]
+	}
+
+	return nil
+}
+
+// GetExits retrieves exits recorded within a time range, oldest first
+func (s *InMemoryStore) GetExits(since, until time.Time) ([]ExitRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []ExitRecord
+	for _, exit := range s.exits {
+		if inRange(exit.Timestamp, since, until) {
+			result = append(result, exit)
+		}
+	}
+
+	return result, nil
+}
+
+// GetExitAnalytics counts exits by reason, container and image within a time range
+func (s *InMemoryStore) GetExitAnalytics(since, until time.Time) (ExitAnalytics, error) {
+	exits, err := s.GetExits(since, until)
+	if err != nil {
+		return ExitAnalytics{}, err
+	}
+
+	analytics := ExitAnalytics{
+		Since:    since,
+		Until:    until,
+		ByReason: make(map[string]int),
+	}
+
+	byContainer := make(map[string]*ExitCount)
+	byImage := make(map[string]*ExitCount)
+
+	for _, exit := range exits {
+		analytics.TotalExits++
+		if exit.Crash {
+			analytics.TotalCrashes++
+		}
+		analytics.ByReason[exit.Reason]++
+
+		countExit(byContainer, exit.ContainerID, exit.ContainerName, exit)
+		countExit(byImage, exit.Image, "", exit)
+	}
+
+	analytics.ByContainer = sortedExitCounts(byContainer)
+	analytics.ByImage = sortedExitCounts(byImage)
+
+	return analytics, nil
+}
+
+// countExit adds an exit to the count for the given key
+func countExit(counts map[string]*ExitCount, key, name string, exit ExitRecord) {
+	count, exists := counts[key]
+	if !exists {
+		count = &ExitCount{
+			Key:      key,
+			Name:     name,
+			ByReason: make(map[string]int),
+		}
+		counts[key] = count
+	}
+
+	count.Exits++
+	if exit.Crash {
+		count.Crashes++
+	}
+	count.ByReason[exit.Reason]++
+	if exit.Timestamp.After(count.LastExit) {
+		count.LastExit = exit.Timestamp
+	}
+}
+
+// sortedExitCounts returns exit counts ordered by crashes, then exits (descending)
+func sortedExitCounts(counts map[string]*ExitCount) []ExitCount {
+	result := make([]ExitCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Crashes != result[j].Crashes {
+			return result[i].Crashes > result[j].Crashes
+		}
+		if result[i].Exits != result[j].Exits {
+			return result[i].Exits > result[j].Exits
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// inRange reports whether t falls within [since, until]; a zero until means no upper bound
+func inRange(t, since, until time.Time) bool {
+	if t.Before(since) {
+		return false
+	}
+	if !until.IsZero() && t.After(until) {
+		return false
+	}
+	return true
+}
@@ -7,11 +7,13 @@ import (
 
 // ContainerEvent represents a container lifecycle event
 type ContainerEvent struct {
-	ContainerID   string    `json:"container_id"`
-	ContainerName string    `json:"container_name"`
-	EventType     string    `json:"event_type"` // "start", "stop", "restart"
-	Timestamp     time.Time `json:"timestamp"`
-	RestartCount  int       `json:"restart_count"`
+	ID            uint64            `json:"id"` // assigned by the store, increasing
+	ContainerID   string            `json:"container_id"`
+	ContainerName string            `json:"container_name"`
+	EventType     string            `json:"event_type"` // "start", "stop", "restart"
+	Timestamp     time.Time         `json:"timestamp"`
+	RestartCount  int               `json:"restart_count"`
+	Details       map[string]string `json:"details,omitempty"`
 }
 
 // MetricSnapshot represents a point-in-time metric reading
@@ -20,9 +22,18 @@ type MetricSnapshot struct {
 	Timestamp   time.Time `json:"timestamp"`
 	CPUPercent  float64   `json:"cpu_percent"`
 	MemUsage    float64   `json:"mem_usage"`
+	MemLimit    float64   `json:"mem_limit"` // in MB
 	MemPercent  float64   `json:"mem_percent"`
 	NetInput    float64   `json:"net_input"`
 	NetOutput   float64   `json:"net_output"`
+
+	// Set on rollups, which hold the mean of a bucket's samples plus their peaks
+	// and the container's identity, so reports can group containers that are gone
+	CPUPeak float64           `json:"cpu_peak,omitempty"`
+	MemPeak float64           `json:"mem_peak,omitempty"` // in MB
+	Name    string            `json:"name,omitempty"`
+	Image   string            `json:"image,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
 }
 
 // HistoryStore interface for storage implementations
@@ -31,14 +42,46 @@ type HistoryStore interface {
 	AddEvent(event ContainerEvent) error
 	GetEvents(containerID string, limit int) ([]ContainerEvent, error)
 	GetAllEvents(limit int) ([]ContainerEvent, error)
+	QueryEvents(query EventQuery) ([]ContainerEvent, error)
+	SubscribeEvents(buffer int) (<-chan ContainerEvent, func())
+	EventsEvicted(after uint64, types []string) (bool, error)
 	
 	// Metrics
 	AddMetric(metric MetricSnapshot) error
 	GetMetrics(containerID string, since time.Time) ([]MetricSnapshot, error)
+	GetMetricSummary(containerID string, since, until time.Time) (MetricSummary, error)
+	GetFleetSummary(since, until time.Time) ([]MetricSummary, error)
+	AddMetricRollup(metric MetricSnapshot) error
+	GetMetricRollups(containerID string, since, until time.Time) ([]MetricSnapshot, error)
+	GetRollupSummary(containerID string, since, until time.Time) (MetricSummary, error)
+	GetFleetRollupSummary(since, until time.Time) ([]MetricSummary, error)
 	
 	// Analytics
 	GetMostRestartedContainers(limit int) ([]ContainerRestartStats, error)
 	GetContainerUptime(containerID string) (time.Duration, error)
+	GetAvailability(since, until time.Time) ([]ContainerAvailability, error)
+	SeedTransitions(containerID, containerName string, startedAt, finishedAt time.Time, running bool) error
+
+	// Exits
+	AddExit(exit ExitRecord) error
+	GetExits(since, until time.Time) ([]ExitRecord, error)
+	GetExitAnalytics(since, until time.Time) (ExitAnalytics, error)
+
+	// Configuration history
+	AddConfigSnapshot(snapshot ConfigSnapshot) (ConfigSnapshot, bool, error)
+	GetConfigHistory(key string) ([]ConfigSnapshot, error)
+
+	// Disk usage
+	AddDiskUsage(snapshot DiskUsageSnapshot) error
+	GetDiskUsageHistory(since, until time.Time) ([]DiskUsageSnapshot, error)
+
+	// Audit log
+	AddAudit(entry AuditEntry) error
+	GetAuditLog(limit int) ([]AuditEntry, error)
+
+	// Host metrics
+	AddHostMetric(metric HostMetric) error
+	GetHostMetrics(since, until time.Time) ([]HostMetric, error)
 }
 
 // ContainerRestartStats holds restart statistics for a container
@@ -51,12 +94,31 @@ type ContainerRestartStats struct {
 
 // InMemoryStore implements HistoryStore using in-memory storage
 type InMemoryStore struct {
-	events  []ContainerEvent
-	metrics []MetricSnapshot
-	mu      sync.RWMutex
+	events      []ContainerEvent
+	metrics     []MetricSnapshot
+	rollups     map[string][]MetricSnapshot
+	hostMetrics []HostMetric
+	exits       []ExitRecord
+	diskUsage   []DiskUsageSnapshot
+	audit       []AuditEntry
+	mu          sync.RWMutex
 	
 	// Track container states for uptime calculation
 	containerStates map[string]containerState
+
+	// Versioned configuration snapshots keyed by container name or compose service
+	configHistory map[string][]ConfigSnapshot
+
+	// Up/down transitions per container for availability reports
+	transitions    map[string][]stateTransition
+	containerNames map[string]string
+
+	// Event IDs and live event subscribers
+	lastEventID uint64
+	subscribers map[chan ContainerEvent]struct{}
+
+	// Highest ID dropped by retention per event type
+	evictedEvents map[string]uint64
 }
 
 type containerState struct {
@@ -70,7 +132,17 @@ func NewInMemoryStore() *InMemoryStore {
 	return &InMemoryStore{
 		events:          make([]ContainerEvent, 0),
 		metrics:         make([]MetricSnapshot, 0),
+		rollups:         make(map[string][]MetricSnapshot),
+		hostMetrics:     make([]HostMetric, 0),
+		exits:           make([]ExitRecord, 0),
+		diskUsage:       make([]DiskUsageSnapshot, 0),
+		audit:           make([]AuditEntry, 0),
 		containerStates: make(map[string]containerState),
+		configHistory:   make(map[string][]ConfigSnapshot),
+		transitions:     make(map[string][]stateTransition),
+		containerNames:  make(map[string]string),
+		subscribers:     make(map[chan ContainerEvent]struct{}),
+		evictedEvents:   make(map[string]uint64),
 	}
 }
 
@@ -79,7 +151,10 @@ func (s *InMemoryStore) AddEvent(event ContainerEvent) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	
+	s.lastEventID++
+	event.ID = s.lastEventID
 	s.events = append(s.events, event)
+	s.publishEvent(event)
 	
 	// Update container state
 	state := s.containerStates[event.ContainerID]
@@ -93,11 +168,10 @@ func (s *InMemoryStore) AddEvent(event ContainerEvent) error {
 		state.isRunning = false
 	}
 	s.containerStates[event.ContainerID] = state
+	s.recordTransition(event)
 	
-	// Keep only last 1000 events to prevent memory bloat
-	if len(s.events) > 1000 {
-		s.events = s.events[len(s.events)-1000:]
-	}
+	// Keep only the last events of each retention class to prevent memory bloat
+	s.trimEvents(event.EventType)
 	
 	return nil
 }
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"time"
+)
+
+// HostMetric represents a point-in-time reading of host-level metrics
+type HostMetric struct {
+	Timestamp      time.Time `json:"timestamp"`
+	CPUPercent     float64   `json:"cpu_percent"`
+	MemUsed        float64   `json:"mem_used"`  // in MB
+	MemTotal       float64   `json:"mem_total"` // in MB
+	MemPercent     float64   `json:"mem_percent"`
+	Load1          float64   `json:"load1"`
+	DiskUsed       float64   `json:"disk_used"`       // in MB
+	DiskReadKBps   float64   `json:"disk_read_kbps"`  // summed over devices
+	DiskWriteKBps  float64   `json:"disk_write_kbps"` // summed over devices
+	NetRxKBps      float64   `json:"net_rx_kbps"`     // summed over interfaces
+	NetTxKBps      float64   `json:"net_tx_kbps"`     // summed over interfaces
+	CPUPressure    float64   `json:"cpu_pressure"`    // PSI "some" avg10
+	MemoryPressure float64   `json:"memory_pressure"` // PSI "some" avg10
+	IOPressure     float64   `json:"io_pressure"`     // PSI "some" avg10
+}
+
+// AddHostMetric adds a host metric reading to the store
+func (s *InMemoryStore) AddHostMetric(metric HostMetric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hostMetrics = append(s.hostMetrics, metric)
+
+	// Keep only last 10000 readings (about 28 hours at 10s intervals)
+	if len(s.hostMetrics) > 10000 {
+		s.hostMetrics = s.hostMetrics[len(s.hostMetrics)-10000:]
+	}
+
+	return nil
+}
+
+// GetHostMetrics retrieves host metric readings within a time range, oldest first
+func (s *InMemoryStore) GetHostMetrics(since, until time.Time) ([]HostMetric, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []HostMetric
+	for _, metric := range s.hostMetrics {
+		if inRange(metric.Timestamp, since, until) {
+			result = append(result, metric)
+		}
+	}
+
+	return result, nil
+}
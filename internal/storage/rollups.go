@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// rollupRetention is how long downsampled metrics are kept, long enough for
+// monthly cost reports and week-long forecasts
+const rollupRetention = 31 * 24 * time.Hour
+
+// AddMetricRollup adds a downsampled metric snapshot to the store. Rollups are kept
+// per container for rollupRetention, independent of the raw metric cap.
+func (s *InMemoryStore) AddMetricRollup(metric MetricSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rollups[metric.ContainerID] = append(s.rollups[metric.ContainerID], metric)
+
+	// Drop rollups past retention, including those of containers that are gone
+	cutoff := metric.Timestamp.Add(-rollupRetention)
+	for id, series := range s.rollups {
+		start := 0
+		for start < len(series) && series[start].Timestamp.Before(cutoff) {
+			start++
+		}
+		if start == len(series) {
+			delete(s.rollups, id)
+		} else if start > 0 {
+			s.rollups[id] = series[start:]
+		}
+	}
+
+	return nil
+}
+
+// GetMetricRollups retrieves a container's downsampled metrics within a time range, oldest first
+func (s *InMemoryStore) GetMetricRollups(containerID string, since, until time.Time) ([]MetricSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []MetricSnapshot
+	for _, metric := range s.rollups[containerID] {
+		if inRange(metric.Timestamp, since, until) {
+			result = append(result, metric)
+		}
+	}
+
+	return result, nil
+}
+
+// GetRollupSummary summarizes a container's downsampled metrics within a time range.
+// CPUPercent and MemUsage describe the rollups' means; CPUPeak and MemPeak describe
+// their peaks, which right-sizing needs to see bursts between samples.
+func (s *InMemoryStore) GetRollupSummary(containerID string, since, until time.Time) (MetricSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var series []MetricSnapshot
+	for _, metric := range s.rollups[containerID] {
+		if inRange(metric.Timestamp, since, until) {
+			series = append(series, metric)
+		}
+	}
+
+	return summarizeRollups(containerID, series, since, until), nil
+}
+
+// GetFleetRollupSummary summarizes the downsampled metrics of every container with
+// rollups in a time range, including containers that have since been removed
+func (s *InMemoryStore) GetFleetRollupSummary(since, until time.Time) ([]MetricSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]MetricSummary, 0, len(s.rollups))
+	for id, rollups := range s.rollups {
+		var series []MetricSnapshot
+		for _, metric := range rollups {
+			if inRange(metric.Timestamp, since, until) {
+				series = append(series, metric)
+			}
+		}
+		if len(series) > 0 {
+			result = append(result, summarizeRollups(id, series, since, until))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ContainerID < result[j].ContainerID
+	})
+
+	return result, nil
+}
+
+// summarizeRollups adds the statistics of the peaks to a rollup series summary
+func summarizeRollups(containerID string, series []MetricSnapshot, since, until time.Time) MetricSummary {
+	summary := summarizeMetrics(containerID, series, since, until)
+
+	cpu := make([]float64, len(series))
+	mem := make([]float64, len(series))
+	for i, metric := range series {
+		cpu[i] = metric.CPUPeak
+		mem[i] = metric.MemPeak
+	}
+	cpuPeak, memPeak := Summarize(cpu), Summarize(mem)
+	summary.CPUPeak = &cpuPeak
+	summary.MemPeak = &memPeak
+
+	if len(series) > 0 {
+		last := series[len(series)-1]
+		summary.Name = last.Name
+		summary.Image = last.Image
+		summary.Labels = last.Labels
+	}
+
+	return summary
+}
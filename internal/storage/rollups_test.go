@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddMetricRollup_Retention(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewInMemoryStore()
+
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "gone", Timestamp: start})
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(time.Hour)})
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(rollupRetention)})
+
+	// A rollup exactly at the retention boundary is kept
+	if _, exists := s.rollups["gone"]; !exists {
+		t.Errorf("rollup at the retention boundary dropped")
+	}
+
+	// The next one pushes the rollup of the removed container past retention
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(rollupRetention + time.Second)})
+	if _, exists := s.rollups["gone"]; exists {
+		t.Errorf("rollups of a removed container kept past retention")
+	}
+	if got, _ := s.GetMetricRollups("web", start, start.Add(2*rollupRetention)); len(got) != 3 {
+		t.Errorf("got %d rollups of web, want 3", len(got))
+	}
+
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(rollupRetention + 2*time.Hour)})
+	if got, _ := s.GetMetricRollups("web", start, start.Add(2*rollupRetention)); len(got) != 3 || !got[0].Timestamp.Equal(start.Add(rollupRetention)) {
+		t.Errorf("retention kept %+v", got)
+	}
+}
+
+func TestGetRollupSummary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := start.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		rollups      []MetricSnapshot
+		wantSamples  int
+		wantCoverage float64
+		wantCPUMax   float64
+		wantPeakMax  float64
+		wantName     string
+	}{
+		{name: "no rollups"},
+		{
+			name:        "single rollup",
+			rollups:     []MetricSnapshot{{Timestamp: start.Add(30 * time.Minute), CPUPercent: 20, CPUPeak: 80, Name: "web"}},
+			wantSamples: 1,
+			wantCPUMax:  20,
+			wantPeakMax: 80,
+			wantName:    "web",
+		},
+		{
+			name: "means and peaks are summarized apart",
+			rollups: []MetricSnapshot{
+				{Timestamp: start, CPUPercent: 10, CPUPeak: 30, Name: "old-name"},
+				{Timestamp: start.Add(30 * time.Minute), CPUPercent: 20, CPUPeak: 95, Name: "web"},
+				{Timestamp: start.Add(2 * time.Hour), CPUPercent: 99, CPUPeak: 99, Name: "later"}, // outside the range
+			},
+			wantSamples:  2,
+			wantCoverage: 0.5,
+			wantCPUMax:   20,
+			wantPeakMax:  95,
+			wantName:     "web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewInMemoryStore()
+			for _, rollup := range tt.rollups {
+				rollup.ContainerID = "web"
+				s.AddMetricRollup(rollup)
+			}
+
+			summary, err := s.GetRollupSummary("web", start, until)
+			if err != nil {
+				t.Fatalf("GetRollupSummary: %v", err)
+			}
+			if summary.Samples != tt.wantSamples || summary.Coverage != tt.wantCoverage || !summary.Partial {
+				t.Errorf("samples %d, coverage %v, partial %v; want %d, %v, true", summary.Samples, summary.Coverage, summary.Partial, tt.wantSamples, tt.wantCoverage)
+			}
+			if summary.CPUPercent.Max != tt.wantCPUMax || summary.CPUPeak == nil || summary.CPUPeak.Max != tt.wantPeakMax {
+				t.Errorf("cpu max %v, peak %+v; want %v and %v", summary.CPUPercent.Max, summary.CPUPeak, tt.wantCPUMax, tt.wantPeakMax)
+			}
+			if summary.Name != tt.wantName {
+				t.Errorf("name %q, want %q", summary.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestGetFleetRollupSummary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewInMemoryStore()
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start})
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "db", Timestamp: start.Add(time.Minute)})
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "old", Timestamp: start.Add(-time.Hour)})
+
+	summaries, _ := s.GetFleetRollupSummary(start, start.Add(time.Hour))
+	if len(summaries) != 2 || summaries[0].ContainerID != "db" || summaries[1].ContainerID != "web" {
+		t.Errorf("got %+v, want db and web in order", summaries)
+	}
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SeriesSummary holds descriptive statistics of a metric series
+type SeriesSummary struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+}
+
+// minSummaryCoverage is the share of the requested range the samples must span
+// for a summary not to be flagged as partial
+const minSummaryCoverage = 0.9
+
+// MetricSummary summarizes a container's metrics over a time range. Since and
+// Until are the requested range; FirstSample and LastSample bound the data that
+// was actually available, and Coverage is the share of the range they span.
+type MetricSummary struct {
+	ContainerID string        `json:"container_id"`
+	Since       time.Time     `json:"since"`
+	Until       time.Time     `json:"until"`
+	Samples     int           `json:"samples"`
+	FirstSample time.Time     `json:"first_sample,omitempty"`
+	LastSample  time.Time     `json:"last_sample,omitempty"`
+	Coverage    float64       `json:"coverage"`
+	Partial     bool          `json:"partial"`           // coverage below minSummaryCoverage
+	Rollups     int           `json:"rollups,omitempty"` // samples taken from rollups, older than the raw history
+	CPUPercent  SeriesSummary `json:"cpu_percent"`
+	MemUsage    SeriesSummary `json:"mem_usage"` // in MB
+	MemPercent  SeriesSummary `json:"mem_percent"`
+	NetInput    SeriesSummary `json:"net_input_kbps"`  // rate between consecutive samples
+	NetOutput   SeriesSummary `json:"net_output_kbps"` // rate between consecutive samples
+
+	// Set on rollup summaries, the identity from the latest rollup
+	CPUPeak *SeriesSummary    `json:"cpu_peak,omitempty"`
+	MemPeak *SeriesSummary    `json:"mem_peak,omitempty"`
+	Name    string            `json:"name,omitempty"`
+	Image   string            `json:"image,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// GetMetricSummary summarizes a container's metrics within a time range. Raw
+// history is capped, so the part of the range it no longer reaches is filled in
+// from rollups.
+func (s *InMemoryStore) GetMetricSummary(containerID string, since, until time.Time) (MetricSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var series []MetricSnapshot
+	for _, metric := range s.metrics {
+		if metric.ContainerID == containerID && inRange(metric.Timestamp, since, until) {
+			series = append(series, metric)
+		}
+	}
+
+	return s.summarizeWithRollups(containerID, series, since, until), nil
+}
+
+// GetFleetSummary summarizes the metrics of every container with samples or
+// rollups in a time range, filling in from rollups like GetMetricSummary
+func (s *InMemoryStore) GetFleetSummary(since, until time.Time) ([]MetricSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byContainer := make(map[string][]MetricSnapshot)
+	for _, metric := range s.metrics {
+		if inRange(metric.Timestamp, since, until) {
+			byContainer[metric.ContainerID] = append(byContainer[metric.ContainerID], metric)
+		}
+	}
+	for id, rollups := range s.rollups {
+		if _, ok := byContainer[id]; ok {
+			continue
+		}
+		for _, metric := range rollups {
+			if inRange(metric.Timestamp, since, until) {
+				byContainer[id] = nil
+				break
+			}
+		}
+	}
+
+	result := make([]MetricSummary, 0, len(byContainer))
+	for id, series := range byContainer {
+		result = append(result, s.summarizeWithRollups(id, series, since, until))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ContainerID < result[j].ContainerID
+	})
+
+	return result, nil
+}
+
+// summarizeWithRollups summarizes a raw series after prepending the container's
+// rollups from the part of the range before its first raw sample. Each rollup
+// counts as one sample of its bucket's means. The caller holds the lock.
+func (s *InMemoryStore) summarizeWithRollups(containerID string, raw []MetricSnapshot, since, until time.Time) MetricSummary {
+	cutoff := until
+	if len(raw) > 0 {
+		cutoff = raw[0].Timestamp
+	}
+
+	var series []MetricSnapshot
+	for _, metric := range s.rollups[containerID] {
+		if inRange(metric.Timestamp, since, until) && metric.Timestamp.Before(cutoff) {
+			series = append(series, metric)
+		}
+	}
+	rollups := len(series)
+	series = append(series, raw...)
+
+	summary := summarizeMetrics(containerID, series, since, until)
+	summary.Rollups = rollups
+	return summary
+}
+
+// summarizeMetrics computes the statistics of a chronological series
+func summarizeMetrics(containerID string, series []MetricSnapshot, since, until time.Time) MetricSummary {
+	summary := MetricSummary{ContainerID: containerID, Since: since, Until: until, Samples: len(series), Partial: true}
+	if len(series) == 0 {
+		return summary
+	}
+	summary.FirstSample = series[0].Timestamp
+	summary.LastSample = series[len(series)-1].Timestamp
+	summary.Coverage = coverage(summary.FirstSample, summary.LastSample, since, until)
+	summary.Partial = summary.Coverage < minSummaryCoverage
+
+	cpu := make([]float64, len(series))
+	mem := make([]float64, len(series))
+	memPercent := make([]float64, len(series))
+	var netIn, netOut []float64
+
+	for i, metric := range series {
+		cpu[i] = metric.CPUPercent
+		mem[i] = metric.MemUsage
+		memPercent[i] = metric.MemPercent
+
+		// Network counters are cumulative; summarize the rate between samples
+		if i == 0 {
+			continue
+		}
+		previous := series[i-1]
+		elapsed := metric.Timestamp.Sub(previous.Timestamp).Seconds()
+		// A counter going backwards means the container restarted
+		if elapsed <= 0 || metric.NetInput < previous.NetInput || metric.NetOutput < previous.NetOutput {
+			continue
+		}
+		netIn = append(netIn, (metric.NetInput-previous.NetInput)/elapsed)
+		netOut = append(netOut, (metric.NetOutput-previous.NetOutput)/elapsed)
+	}
+
+	summary.CPUPercent = Summarize(cpu)
+	summary.MemUsage = Summarize(mem)
+	summary.MemPercent = Summarize(memPercent)
+	summary.NetInput = Summarize(netIn)
+	summary.NetOutput = Summarize(netOut)
+
+	return summary
+}
+
+// coverage returns the share of a range spanned by the samples between first and last
+func coverage(first, last, since, until time.Time) float64 {
+	span := until.Sub(since)
+	if span <= 0 {
+		return 0
+	}
+	return math.Min(last.Sub(first).Seconds()/span.Seconds(), 1)
+}
+
+// Summarize computes min, max, mean, population standard deviation and percentiles
+func Summarize(values []float64) SeriesSummary {
+	if len(values) == 0 {
+		return SeriesSummary{}
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(len(sorted))
+
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return SeriesSummary{
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		StdDev: math.Sqrt(squares / float64(len(sorted))),
+		P50:    Percentile(sorted, 50),
+		P90:    Percentile(sorted, 90),
+		P95:    Percentile(sorted, 95),
+		P99:    Percentile(sorted, 99),
+	}
+}
+
+// Percentile interpolates linearly between the closest ranks of a sorted slice
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]float64{5}, 0, 5},
+		{[]float64{5}, 99, 5},
+		{[]float64{10, 20, 30, 40}, 0, 10},
+		{[]float64{10, 20, 30, 40}, 50, 25},
+		{[]float64{10, 20, 30, 40}, 90, 37},
+		{[]float64{10, 20, 30, 40}, 100, 40},
+		{[]float64{10, 20, 30}, 50, 20},
+	}
+
+	for _, tt := range tests {
+		if got := Percentile(tt.sorted, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   SeriesSummary
+	}{
+		{name: "empty", values: nil, want: SeriesSummary{}},
+		{name: "single", values: []float64{7}, want: SeriesSummary{Min: 7, Max: 7, Mean: 7, P50: 7, P90: 7, P95: 7, P99: 7}},
+		{
+			name:   "unsorted",
+			values: []float64{9, 2, 5, 4, 4, 7, 4, 5},
+			want:   SeriesSummary{Min: 2, Max: 9, Mean: 5, StdDev: 2, P50: 4.5, P90: 7.6, P95: 8.3, P99: 8.86},
+		},
+	}
+
+	for _, tt := range tests {
+		got := Summarize(tt.values)
+		fields := []struct {
+			field     string
+			got, want float64
+		}{
+			{"min", got.Min, tt.want.Min},
+			{"max", got.Max, tt.want.Max},
+			{"mean", got.Mean, tt.want.Mean},
+			{"stddev", got.StdDev, tt.want.StdDev},
+			{"p50", got.P50, tt.want.P50},
+			{"p90", got.P90, tt.want.P90},
+			{"p95", got.P95, tt.want.P95},
+			{"p99", got.P99, tt.want.P99},
+		}
+		for _, f := range fields {
+			if math.Abs(f.got-f.want) > 1e-9 {
+				t.Errorf("%s: %s = %v, want %v", tt.name, f.field, f.got, f.want)
+			}
+		}
+	}
+}
+
+func TestSummarizeMetrics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := start.Add(time.Minute)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	tests := []struct {
+		name         string
+		series       []MetricSnapshot
+		wantSamples  int
+		wantCoverage float64
+		wantPartial  bool
+		wantNetMax   float64
+		wantNetMean  float64
+	}{
+		{name: "empty history", wantPartial: true},
+		{
+			name:        "single sample",
+			series:      []MetricSnapshot{{Timestamp: at(30), CPUPercent: 10, NetInput: 100}},
+			wantSamples: 1,
+			wantPartial: true,
+		},
+		{
+			name: "full range",
+			series: []MetricSnapshot{
+				{Timestamp: at(0), NetInput: 0},
+				{Timestamp: at(30), NetInput: 300},
+				{Timestamp: at(60), NetInput: 1200},
+			},
+			wantSamples:  3,
+			wantCoverage: 1,
+			wantNetMax:   30,
+			wantNetMean:  20,
+		},
+		{
+			name: "counter reset is skipped",
+			series: []MetricSnapshot{
+				{Timestamp: at(0), NetInput: 0},
+				{Timestamp: at(30), NetInput: 300},
+				{Timestamp: at(60), NetInput: 100},
+			},
+			wantSamples:  3,
+			wantCoverage: 1,
+			wantNetMax:   10,
+			wantNetMean:  10,
+		},
+		{
+			name: "half the range",
+			series: []MetricSnapshot{
+				{Timestamp: at(30)},
+				{Timestamp: at(60)},
+			},
+			wantSamples:  2,
+			wantCoverage: 0.5,
+			wantPartial:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		got := summarizeMetrics("web", tt.series, start, until)
+		if got.Samples != tt.wantSamples {
+			t.Errorf("%s: samples = %d, want %d", tt.name, got.Samples, tt.wantSamples)
+		}
+		if math.Abs(got.Coverage-tt.wantCoverage) > 1e-9 {
+			t.Errorf("%s: coverage = %v, want %v", tt.name, got.Coverage, tt.wantCoverage)
+		}
+		if got.Partial != tt.wantPartial {
+			t.Errorf("%s: partial = %v, want %v", tt.name, got.Partial, tt.wantPartial)
+		}
+		if got.NetInput.Max != tt.wantNetMax || got.NetInput.Mean != tt.wantNetMean {
+			t.Errorf("%s: net input max/mean = %v/%v, want %v/%v", tt.name, got.NetInput.Max, got.NetInput.Mean, tt.wantNetMax, tt.wantNetMean)
+		}
+	}
+}
+
+func TestGetMetricSummary_FillsFromRollups(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := start.Add(time.Hour)
+	s := NewInMemoryStore()
+
+	// Rollups older than the first raw sample fill the start of the range;
+	// the one overlapping raw history is not counted twice
+	for _, minutes := range []int{10, 20, 40} {
+		s.AddMetricRollup(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(time.Duration(minutes) * time.Minute), CPUPercent: 50})
+	}
+	for _, minutes := range []int{30, 50} {
+		s.AddMetric(MetricSnapshot{ContainerID: "web", Timestamp: start.Add(time.Duration(minutes) * time.Minute), CPUPercent: 10})
+	}
+	s.AddMetricRollup(MetricSnapshot{ContainerID: "gone", Timestamp: start.Add(5 * time.Minute), CPUPercent: 5})
+
+	summary, _ := s.GetMetricSummary("web", start, until)
+	if summary.Samples != 4 || summary.Rollups != 2 {
+		t.Errorf("samples/rollups = %d/%d, want 4/2", summary.Samples, summary.Rollups)
+	}
+	if !summary.FirstSample.Equal(start.Add(10*time.Minute)) || !summary.LastSample.Equal(start.Add(50*time.Minute)) {
+		t.Errorf("samples span %v to %v", summary.FirstSample, summary.LastSample)
+	}
+	if summary.CPUPercent.Mean != 30 {
+		t.Errorf("cpu mean = %v, want 30", summary.CPUPercent.Mean)
+	}
+
+	// Without raw history the rollups alone are summarized
+	summary, _ = s.GetMetricSummary("gone", start, until)
+	if summary.Samples != 1 || summary.Rollups != 1 {
+		t.Errorf("gone: samples/rollups = %d/%d, want 1/1", summary.Samples, summary.Rollups)
+	}
+
+	fleet, _ := s.GetFleetSummary(start, until)
+	if len(fleet) != 2 || fleet[0].ContainerID != "gone" || fleet[1].ContainerID != "web" {
+		t.Fatalf("fleet summary = %+v", fleet)
+	}
+	if fleet[1].Rollups != 2 {
+		t.Errorf("fleet web rollups = %d, want 2", fleet[1].Rollups)
+	}
+}
@@ -0,0 +1,297 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"gocontainerops/internal/container"
+)
+
+// CollectFunc gathers the current stats of every container to broadcast
+type CollectFunc func(ctx context.Context) ([]container.ContainerData, error)
+
+// Update is a single message pushed to a subscriber. Containers new to the
+// subscriber are sent whole; for the others only "id" and the fields that changed
+// are sent, with null for fields that went away.
+type Update struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Full      bool             `json:"full"`    // the first update carries every matching container
+	Changed   []map[string]any `json:"changed"` // containers that are new or differ from the last update
+	Removed   []string         `json:"removed,omitempty"`
+	Skipped   int              `json:"skipped,omitempty"` // samples merged into this update because the client fell behind
+}
+
+// volatileFields are derived from the clock and would mark every container as
+// changed on every sample; clients derive them from state and created
+var volatileFields = map[string]bool{
+	"uptime": true,
+}
+
+// fieldThresholds are the smallest changes of a metric worth sending, compared with
+// the value the subscriber last received so slow drifts still get through
+var fieldThresholds = map[string]float64{
+	"cpu_percent":  0.5, // percentage points
+	"mem_percent":  0.5, // percentage points
+	"mem_usage":    1,   // MB
+	"net_input":    64,  // KB
+	"net_output":   64,
+	"block_input":  64,
+	"block_output": 64,
+}
+
+// sample is a container's data together with its JSON fields, flattened once per sample
+type sample struct {
+	data   container.ContainerData
+	fields map[string]any
+}
+
+// Hub runs a single shared collector and serves diffs of its latest sample to
+// any number of subscribers. The collector only runs while someone is subscribed.
+type Hub struct {
+	interval time.Duration
+	collect  CollectFunc
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	latest      map[string]sample
+	sampledAt   time.Time
+	seq         uint64
+	cancel      context.CancelFunc
+}
+
+// Subscription follows a hub at its own pace. Each call to Next diffs the hub's
+// latest sample against what this subscriber last received, so a slow client
+// skips intermediate samples instead of queueing them.
+type Subscription struct {
+	hub    *Hub
+	filter func(container.ContainerData) bool
+
+	sent    map[string]map[string]any // fields last sent, per container
+	lastSeq uint64
+}
+
+// NewHub creates a hub sampling with collect every interval
+func NewHub(interval time.Duration, collect CollectFunc) *Hub {
+	return &Hub{
+		interval:    interval,
+		collect:     collect,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Interval returns the sampling interval of the shared collector
+func (h *Hub) Interval() time.Duration {
+	return h.interval
+}
+
+// Subscribe registers a subscriber receiving the containers matching filter (all when nil)
+func (h *Hub) Subscribe(filter func(container.ContainerData) bool) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := &Subscription{hub: h, filter: filter}
+	h.subscribers[s] = struct{}{}
+
+	if h.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.cancel = cancel
+		go h.run(ctx)
+	}
+
+	return s
+}
+
+// Close unregisters the subscription, stopping the collector after the last one
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, s)
+	if len(h.subscribers) == 0 && h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+		h.latest = nil
+		h.seq = 0
+	}
+}
+
+// Next returns the changes since this subscriber's previous update, or false when
+// the hub has not sampled since then
+func (s *Subscription) Next() (Update, bool) {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.seq == 0 || h.seq == s.lastSeq {
+		return Update{}, false
+	}
+
+	update := Update{Timestamp: h.sampledAt, Full: s.sent == nil, Changed: []map[string]any{}}
+	if s.lastSeq > 0 {
+		update.Skipped = int(h.seq - s.lastSeq - 1)
+	}
+
+	sent := make(map[string]map[string]any)
+	var names []string // sort key of each changed entry
+	for id, current := range h.latest {
+		if s.filter != nil && !s.filter(current.data) {
+			continue
+		}
+
+		previous, exists := s.sent[id]
+		if !exists {
+			sent[id] = current.fields
+			update.Changed = append(update.Changed, current.fields)
+			names = append(names, current.data.Name)
+			continue
+		}
+
+		changes := diffFields(previous, current.fields)
+		if len(changes) == 0 {
+			sent[id] = previous
+			continue
+		}
+
+		// Remember what the client now holds, not the latest sample
+		merged := make(map[string]any, len(previous)+len(changes))
+		for key, value := range previous {
+			merged[key] = value
+		}
+		for key, value := range changes {
+			if value == nil {
+				delete(merged, key)
+			} else {
+				merged[key] = value
+			}
+		}
+		sent[id] = merged
+
+		changes["id"] = id
+		update.Changed = append(update.Changed, changes)
+		names = append(names, current.data.Name)
+	}
+	for id := range s.sent {
+		if _, exists := sent[id]; !exists {
+			update.Removed = append(update.Removed, id)
+		}
+	}
+
+	sort.Sort(byName{update.Changed, names})
+	sort.Strings(update.Removed)
+
+	s.sent = sent
+	s.lastSeq = h.seq
+
+	return update, true
+}
+
+// run samples on every interval until the last subscriber leaves
+func (h *Hub) run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		// Bound each sample so a stuck daemon call cannot stall the stream
+		sampleCtx, cancel := context.WithTimeout(ctx, h.interval*5)
+		results, err := h.collect(sampleCtx)
+		cancel()
+
+		// A failed sample keeps the previous one, so subscribers are not told every
+		// container was removed and then re-added
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Error collecting stream stats: %v", err)
+			}
+		} else {
+			h.publish(ctx, results)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish replaces the latest sample
+func (h *Hub) publish(ctx context.Context, results []container.ContainerData) {
+	latest := make(map[string]sample, len(results))
+	for _, data := range results {
+		latest[data.ID] = sample{data: data, fields: flatten(data)}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A cancelled collector must not overwrite the state of its successor
+	if ctx.Err() != nil {
+		return
+	}
+	h.latest = latest
+	h.sampledAt = time.Now()
+	h.seq++
+}
+
+// flatten converts a container to its JSON fields
+func flatten(data container.ContainerData) map[string]any {
+	fields := make(map[string]any)
+	if encoded, err := json.Marshal(data); err == nil {
+		json.Unmarshal(encoded, &fields)
+	}
+	return fields
+}
+
+// diffFields returns the fields of current that differ from previous, ignoring
+// volatile fields and metric changes below their threshold. Fields missing from
+// current are returned as nil.
+func diffFields(previous, current map[string]any) map[string]any {
+	changes := make(map[string]any)
+	for key, value := range current {
+		if volatileFields[key] {
+			continue
+		}
+		if old, exists := previous[key]; exists && unchanged(key, old, value) {
+			continue
+		}
+		changes[key] = value
+	}
+	for key := range previous {
+		if _, exists := current[key]; !exists && !volatileFields[key] {
+			changes[key] = nil
+		}
+	}
+	return changes
+}
+
+// unchanged compares a field's values, applying the field's threshold to numbers
+func unchanged(key string, old, value any) bool {
+	if threshold, ok := fieldThresholds[key]; ok {
+		a, aok := old.(float64)
+		b, bok := value.(float64)
+		if aok && bok {
+			return math.Abs(a-b) < threshold
+		}
+	}
+	return reflect.DeepEqual(old, value)
+}
+
+// byName sorts update entries by container name
+type byName struct {
+	entries []map[string]any
+	names   []string
+}
+
+func (b byName) Len() int           { return len(b.entries) }
+func (b byName) Less(i, j int) bool { return b.names[i] < b.names[j] }
+func (b byName) Swap(i, j int) {
+	b.entries[i], b.entries[j] = b.entries[j], b.entries[i]
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gocontainerops/internal/container"
+)
+
+func TestSubscription_NextFieldDiff(t *testing.T) {
+	hub := NewHub(time.Second, nil)
+	sub := &Subscription{hub: hub}
+	ctx := context.Background()
+
+	web := container.ContainerData{ID: "web", Name: "web", State: "running", CPUPercent: 10, MemUsage: 100, Uptime: 60}
+	hub.publish(ctx, []container.ContainerData{web})
+
+	first, ok := sub.Next()
+	if !ok || !first.Full || len(first.Changed) != 1 || first.Changed[0]["uptime"] != float64(60) {
+		t.Fatalf("first update: %+v", first)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*container.ContainerData)
+		want   map[string]any // nil when nothing should be sent
+	}{
+		{"uptime only", func(d *container.ContainerData) { d.Uptime += 2 }, nil},
+		{"cpu below threshold", func(d *container.ContainerData) { d.CPUPercent += 0.2 }, nil},
+		{"cpu drift adds up", func(d *container.ContainerData) { d.CPUPercent += 0.4 }, map[string]any{"id": "web", "cpu_percent": 10.6}},
+		{"state", func(d *container.ContainerData) { d.State = "paused" }, map[string]any{"id": "web", "state": "paused"}},
+		{"health appears", func(d *container.ContainerData) { d.Health = &container.HealthInfo{Status: "healthy"} }, nil},
+	}
+
+	for _, tt := range tests {
+		tt.change(&web)
+		hub.publish(ctx, []container.ContainerData{web})
+		update, ok := sub.Next()
+		if !ok {
+			t.Fatalf("%s: no update", tt.name)
+		}
+		if tt.want == nil && tt.name != "health appears" {
+			if len(update.Changed) != 0 {
+				t.Errorf("%s: sent %v", tt.name, update.Changed)
+			}
+			continue
+		}
+		if len(update.Changed) != 1 {
+			t.Fatalf("%s: got %d changes", tt.name, len(update.Changed))
+		}
+		got := update.Changed[0]
+		if tt.name == "health appears" {
+			if _, ok := got["health"]; !ok || len(got) != 2 {
+				t.Errorf("%s: got %v", tt.name, got)
+			}
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		for key, value := range tt.want {
+			if f, ok := value.(float64); ok {
+				if g, _ := got[key].(float64); g < f-1e-9 || g > f+1e-9 {
+					t.Errorf("%s: %s = %v, want %v", tt.name, key, got[key], value)
+				}
+			} else if got[key] != value {
+				t.Errorf("%s: %s = %v, want %v", tt.name, key, got[key], value)
+			}
+		}
+	}
+
+	// A field that goes away is sent as null
+	web.Health = nil
+	hub.publish(ctx, []container.ContainerData{web})
+	update, _ := sub.Next()
+	if len(update.Changed) != 1 || update.Changed[0]["health"] != nil {
+		t.Errorf("health removal: %v", update.Changed)
+	} else if _, sent := update.Changed[0]["health"]; !sent {
+		t.Errorf("health removal not sent: %v", update.Changed)
+	}
+
+	hub.publish(ctx, nil)
+	update, _ = sub.Next()
+	if len(update.Removed) != 1 || update.Removed[0] != "web" {
+		t.Errorf("removal: %+v", update)
+	}
+}
+
+func TestHub_FailedCollectKeepsSnapshot(t *testing.T) {
+	var calls atomic.Int32
+	hub := NewHub(10*time.Millisecond, func(ctx context.Context) ([]container.ContainerData, error) {
+		if calls.Add(1) == 1 {
+			return []container.ContainerData{{ID: "web", Name: "web"}}, nil
+		}
+		return nil, errors.New("daemon unavailable")
+	})
+
+	sub := hub.Subscribe(nil)
+	defer sub.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	update, ok := sub.Next()
+	if !ok || len(update.Changed) != 1 {
+		t.Fatalf("first update: %+v", update)
+	}
+	if _, ok := sub.Next(); ok {
+		t.Errorf("failed samples produced an update")
+	}
+}
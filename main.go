@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"gocontainerops/internal/config"
+	"gocontainerops/internal/container"
 	"gocontainerops/internal/docker"
 	"gocontainerops/internal/handler"
+	"gocontainerops/internal/host"
+	"gocontainerops/internal/monitor"
+	"gocontainerops/internal/registry"
+	"gocontainerops/internal/security"
 	"gocontainerops/internal/storage"
 )
 
 func main() {
+	// Load configuration from the environment
+	cfg := config.Load()
+
 	// Initialize Docker Client
 	dockerClient, err := docker.NewClient()
 	if err != nil {
@@ -21,10 +32,99 @@ func main() {
 	historyStore := storage.NewInMemoryStore()
 	log.Println("Using in-memory history store")
 
+	// Detect secrets in environments and redact them from served logs
+	secretScanner, err := security.NewSecretScanner(cfg.SecretScanPatterns, cfg.LogRedaction)
+	if err != nil {
+		log.Fatalf("Error configuring secret scanner: %v", err)
+	}
+
+	// Flag containers that restart or flap health too often
+	crashLoops := container.NewCrashLoopDetector(container.DefaultCrashLoopConfig())
+
+	// Compare each container's metrics with its own baseline
+	anomalies := container.NewAnomalyDetector(container.DefaultAnomalyConfig())
+
+	// Follow Docker events to record lifecycle changes and classify exits
+	watcher := monitor.NewWatcher(dockerClient, historyStore)
+	watcher.CrashLoops = crashLoops
+	watcher.Anomalies = anomalies
+
+	// Keep one streaming stats subscription per running container, started and
+	// stopped by the watcher on lifecycle events
+	statsManager := monitor.NewStatsManager(dockerClient)
+	watcher.Stats = statsManager
+	go statsManager.Run(context.Background())
+	go watcher.Run(context.Background())
+
+	// Run detectors and record metric rollups at a fixed cadence, independent of API clients
+	sampleInterval := cfg.SampleInterval
+	if sampleInterval <= 0 {
+		sampleInterval = 10 * time.Second
+	}
+	sampler := monitor.NewSampler(dockerClient, historyStore, statsManager, sampleInterval, cfg.RollupInterval)
+	sampler.CrashLoops = crashLoops
+	sampler.Anomalies = anomalies
+	sampler.Watcher = watcher
+	go sampler.Run(context.Background())
+
+	// Record disk usage over time to chart growth
+	if cfg.DiskUsageInterval > 0 {
+		diskUsage := monitor.NewDiskUsageTracker(dockerClient, historyStore, cfg.DiskUsageInterval)
+		go diskUsage.Run(context.Background())
+	}
+
+	// Sample host cpu, memory, disk, network and pressure metrics
+	var hostCollector *host.Collector
+	if cfg.HostInterval > 0 {
+		hostCollector = host.NewCollector(cfg.HostProcPath, cfg.HostSysPath, cfg.HostDiskPath)
+		go monitor.NewHostTracker(hostCollector, historyStore, cfg.HostInterval).Run(context.Background())
+	}
+
+	// Detect host cpu and io contention and name the containers behind it
+	var contention *container.ContentionDetector
+	if cfg.ContentionInterval > 0 && hostCollector != nil {
+		if hostCollector.PressureAvailable() {
+			contention = container.NewContentionDetector(container.DefaultContentionConfig())
+			go monitor.NewContentionTracker(dockerClient, historyStore, hostCollector, statsManager, contention, cfg.ContentionInterval).Run(context.Background())
+		} else {
+			log.Printf("Contention detection disabled: the kernel does not report pressure stall information under %s/pressure", cfg.HostProcPath)
+		}
+	}
+
+	// Predict memory and disk exhaustion from recorded history
+	var forecastTracker *monitor.ForecastTracker
+	if cfg.ForecastInterval > 0 {
+		forecastTracker = monitor.NewForecastTracker(dockerClient, historyStore, hostCollector, cfg.ForecastInterval, cfg.ForecastHorizon)
+		go forecastTracker.Run(context.Background())
+	}
+
+	// Periodically compare running image tags with the registry
+	var updateChecker *registry.Checker
+	if cfg.UpdateCheckInterval > 0 {
+		dockerConfig, err := registry.LoadDockerConfig()
+		if err != nil {
+			log.Printf("Error loading docker config, using anonymous registry access: %v", err)
+			dockerConfig = &registry.DockerConfig{}
+		}
+		registryClient := registry.NewClient(cfg.UpdateRegistry, dockerConfig)
+		updateChecker = registry.NewChecker(dockerClient, registryClient, cfg.UpdateCheckInterval, cfg.UpdateSemver)
+		go updateChecker.Run(context.Background())
+	}
+
 	// Initialize Handler with DockerService and HistoryStore
 	appHandler := &handler.Handler{
 		DockerService: dockerClient,
 		HistoryStore:  historyStore,
+		CrashLoops:    crashLoops,
+		Anomalies:     anomalies,
+		Contention:    contention,
+		Config:        cfg,
+		Secrets:       secretScanner,
+		Updates:       updateChecker,
+		Host:          hostCollector,
+		Stats:         statsManager,
+		Watcher:       watcher,
+		Forecasts:     forecastTracker,
 	}
 
 	// Serve Static Files
@@ -34,10 +134,40 @@ func main() {
 	// API Endpoints
 	http.HandleFunc("/api/stats", appHandler.HandleStats)
 	http.HandleFunc("/api/metrics/aggregate", appHandler.HandleAggregateMetrics)
+	http.HandleFunc("/api/metrics/summary", appHandler.HandleFleetSummary)
 	http.HandleFunc("/api/logs/", appHandler.HandleLogs)
 	http.HandleFunc("/api/processes/", appHandler.HandleProcesses)
 	http.HandleFunc("/api/history/", appHandler.HandleContainerHistory)
 	http.HandleFunc("/api/events", appHandler.HandleEvents)
+	http.HandleFunc("/api/events/stream", appHandler.HandleEventStream)
+	http.HandleFunc("/api/annotations/", appHandler.HandleAnnotations)
+	http.HandleFunc("/api/projects", appHandler.HandleProjects)
+	http.HandleFunc("/api/projects/", appHandler.HandleProjects)
+	http.HandleFunc("/api/containers/", appHandler.HandleContainer)
+	http.HandleFunc("/api/health/", appHandler.HandleHealth)
+	http.HandleFunc("/api/security/audit", appHandler.HandleSecurityAudit)
+	http.HandleFunc("/api/security/secrets", appHandler.HandleSecretScan)
+	http.HandleFunc("/api/analytics/exits", appHandler.HandleExitAnalytics)
+	http.HandleFunc("/api/analytics/restarts", appHandler.HandleRestartAnalytics)
+	http.HandleFunc("/api/analytics/availability", appHandler.HandleAvailability)
+	http.HandleFunc("/api/anomalies", appHandler.HandleAnomalies)
+	http.HandleFunc("/api/forecasts", appHandler.HandleForecasts)
+	http.HandleFunc("/api/recommendations", appHandler.HandleRecommendations)
+	http.HandleFunc("/api/recommendations/", appHandler.HandleRecommendations)
+	http.HandleFunc("/api/contention", appHandler.HandleContention)
+	http.HandleFunc("/api/costs", appHandler.HandleCosts)
+	http.HandleFunc("/api/images", appHandler.HandleImages)
+	http.HandleFunc("/api/images/", appHandler.HandleImages)
+	http.HandleFunc("/api/updates", appHandler.HandleUpdates)
+	http.HandleFunc("/api/volumes", appHandler.HandleVolumes)
+	http.HandleFunc("/api/networks", appHandler.HandleNetworks)
+	http.HandleFunc("/api/system/df", appHandler.HandleDiskUsage)
+	http.HandleFunc("/api/system/df/", appHandler.HandleDiskUsage)
+	http.HandleFunc("/api/prune/", appHandler.HandlePrune)
+	http.HandleFunc("/api/audit", appHandler.HandleAuditLog)
+	http.HandleFunc("/api/host", appHandler.HandleHost)
+	http.HandleFunc("/api/host/", appHandler.HandleHost)
+	http.HandleFunc("/api/stream/stats", appHandler.HandleStreamStats)
 
 	fmt.Println("Server starting on :8080...")
 	fmt.Println("📊 Dashboard: http://localhost:8080")